@@ -0,0 +1,88 @@
+package dijct
+
+import "reflect"
+
+// isCaptive は f のインスタンスが dep のインスタンスより長く保持されるかを返します
+func isCaptive(f, dep *factoryInfo) bool {
+	return f.lifetimeScope.rank() > dep.lifetimeScope.rank()
+}
+
+// getDependencies は key を依存先として解決する場合に使用されるコンポーネントを返します
+func (c *container) getDependencies(key componentKey) []keyedComponent {
+	if cmp, ok := c.getComponent(key); ok {
+		return []keyedComponent{{key: key, component: cmp}}
+	}
+	if elem, ok := unwrapOptional(key); ok {
+		return c.getDependencies(elem)
+	}
+	if elem, ok := unwrapDeferred(key); ok {
+		return c.getDependencies(elem)
+	}
+	if key.name != "" {
+		return nil
+	}
+	var elems []keyedComponent
+	for _, elem := range c.getComponents(collectionElem(key.t)) {
+		if key.t.Kind() == reflect.Map && elem.key.name == "" {
+			continue
+		}
+		elems = append(elems, elem)
+	}
+	return elems
+}
+
+// collectionElem は t が []T または map[string]T であれば T を、そうでなければ nil を返します
+func collectionElem(t reflect.Type) reflect.Type {
+	switch {
+	case t.Kind() == reflect.Slice:
+		return t.Elem()
+	case t.Kind() == reflect.Map && t.Key() == stringType:
+		return t.Elem()
+	}
+	return nil
+}
+
+// dependsOn は dep を依存先として解決する場合に key の登録が使用されるかを返します
+func dependsOn(dep componentKey, key componentKey) bool {
+	dep = unwrap(dep)
+	if dep == key {
+		return true
+	}
+	if dep.name != "" || collectionElem(dep.t) != key.t {
+		return false
+	}
+	return dep.t.Kind() == reflect.Slice || key.name != ""
+}
+
+// isResolvable は key を依存先として解決できるかを返します
+func (c *container) isResolvable(key componentKey, s *scope) bool {
+	return c.isBuiltin(key, s) || len(c.getDependencies(key)) > 0
+}
+
+// verifyCaptive は elem が依存するコンポーネントのうち、ライフタイムの短いものを CaptiveDependencyError で返します
+func (c *container) verifyCaptive(elem keyedComponent) error {
+	f := elem.component.factoryInfo
+	for _, d := range f.dependencies() {
+		for _, dep := range c.getDependencies(d.key) {
+			if isCaptive(f, dep.component.factoryInfo) {
+				return newCaptiveDependencyError(elem.key, f, dep.key, dep.component.factoryInfo)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyCaptiveRegistration は key で f を登録した場合に、f の依存先と f に依存する登録済みのコンポーネントのライフタイムを検証します
+func (c *container) verifyCaptiveRegistration(key componentKey, f *factoryInfo) error {
+	if err := c.verifyCaptive(keyedComponent{key: key, component: component{factoryInfo: f}}); err != nil {
+		return err
+	}
+	for _, elem := range c.getAllComponents() {
+		for _, dep := range elem.component.factoryInfo.dependencies() {
+			if dependsOn(dep.key, key) && isCaptive(elem.component.factoryInfo, f) {
+				return newCaptiveDependencyError(elem.key, elem.component.factoryInfo, key, f)
+			}
+		}
+	}
+	return nil
+}
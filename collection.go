@@ -0,0 +1,88 @@
+package dijct
+
+import (
+	"reflect"
+	"sort"
+)
+
+var stringType = reflect.TypeOf("")
+
+// resolveCollection は []T または map[string]T を T の全ての登録から解決します。
+// key が集合でない場合や T が登録されていない場合は ok が false になります
+func (c *container) resolveCollection(key componentKey, rc *resolveContext) (v *reflect.Value, ok bool, err error) {
+	if key.name != "" {
+		return nil, false, nil
+	}
+	switch key.t.Kind() {
+	case reflect.Slice:
+		elems := c.getComponents(key.t.Elem())
+		if len(elems) == 0 {
+			return nil, false, nil
+		}
+		s := reflect.MakeSlice(key.t, len(elems), len(elems))
+		for i, elem := range elems {
+			v, err := c.resolveComponent(elem.key, elem.component, rc)
+			if err != nil {
+				return nil, true, err
+			}
+			s.Index(i).Set(*v)
+		}
+		return &s, true, nil
+	case reflect.Map:
+		if key.t.Key() != stringType {
+			return nil, false, nil
+		}
+		elems := c.getComponents(key.t.Elem())
+		m := reflect.MakeMap(key.t)
+		for _, elem := range elems {
+			if elem.key.name == "" {
+				continue
+			}
+			v, err := c.resolveComponent(elem.key, elem.component, rc)
+			if err != nil {
+				return nil, true, err
+			}
+			m.SetMapIndex(reflect.ValueOf(elem.key.name), *v)
+		}
+		if m.Len() == 0 {
+			return nil, false, nil
+		}
+		return &m, true, nil
+	}
+	return nil, false, nil
+}
+
+type keyedComponent struct {
+	key       componentKey
+	component component
+}
+
+// getComponents は t で登録された全てのコンポーネントを登録順に返します
+func (c *container) getComponents(t reflect.Type) []keyedComponent {
+	var elems []keyedComponent
+	for _, elem := range c.getAllComponents() {
+		if elem.key.t == t {
+			elems = append(elems, elem)
+		}
+	}
+	return elems
+}
+
+// getAllComponents は親コンテナも含めて登録された全てのコンポーネントを登録順に返します。
+// 1回の登録で複数のコンポーネントを登録した場合は返り値の順に返します
+func (c *container) getAllComponents() []keyedComponent {
+	var elems []keyedComponent
+	for key := range c.getKeys() {
+		for _, cmp := range c.getComponentsByKey(key) {
+			elems = append(elems, keyedComponent{key: key, component: cmp})
+		}
+	}
+	sort.SliceStable(elems, func(i, j int) bool {
+		a, b := elems[i].component, elems[j].component
+		if a.seq != b.seq {
+			return a.seq < b.seq
+		}
+		return a.index < b.index
+	})
+	return elems
+}
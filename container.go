@@ -1,235 +1,619 @@
 package dijct
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type (
 	container struct {
-		factoryInfos                map[reflect.Type]factoryInfo
-		cache                       map[reflect.Type]reflect.Value
+		mu      sync.RWMutex
+		parent  *container
+		options ContainerOptions
+		// err は NewContainer のオプションが正しくない場合のエラーです。登録と解決の際に返します
+		err                         error
+		components                  map[componentKey]componentList
+		cache                       map[*factoryInfo][]reflect.Value
+		disposables                 []disposable
+		decorators                  map[reflect.Type][]*factoryInfo
+		decorations                 decorations
 		containerInterfaceType      reflect.Type
 		ioCContainerInterfaceType   reflect.Type
 		serviceLocatorInterfaceType reflect.Type
+		scopeInterfaceType          reflect.Type
 	}
 	// Container は DIコンテナーです
 	Container interface {
 		Register(constructor Target, options ...RegisterOptions) error
+		// Replace は既存の登録を置き換えます。テストでフェイクに差し替える場合などに使用します
+		Replace(constructor Target, options ...RegisterOptions) error
+		// Decorate は解決される T を func(T, deps...) T で包むデコレーターを登録します
+		Decorate(decorator Target) error
+		// Close はコンテナが生成した ContainerManaged のインスタンスを生成と逆の順序で破棄します
+		Close(ctx context.Context) error
+		// Graph は登録されたコンポーネントの依存関係のグラフを生成します
+		Graph() *Graph
+		// Has は名前を指定せずに登録された T が登録されているかを返します
+		Has(t reflect.Type) bool
+		// HasNamed は名前付きで登録された T が登録されているかを返します
+		HasNamed(t reflect.Type, name string) bool
+		// Unregister はこのコンテナでの名前を指定せずに登録された T の登録を削除します
+		Unregister(t reflect.Type)
+		// UnregisterNamed はこのコンテナでの名前付きで登録された T の登録を削除します
+		UnregisterNamed(t reflect.Type, name string)
+		// Registrations は登録されたコンポーネントの一覧を返します
+		Registrations() []Registration
 		IoCContainer
 	}
 	// IoCContainer です
 	IoCContainer interface {
 		ServiceLocator
 		CreateChildContainer() Container
+		// BeginScope は ScopeManaged のインスタンスを共有するスコープを開始します
+		BeginScope() Scope
 	}
 	// ServiceLocator です
 	ServiceLocator interface {
 		Invoke(invoker Invoker) error
+		InvokeContext(ctx context.Context, invoker Invoker) error
+		ResolveNamed(t reflect.Type, name string) (interface{}, error)
+		// Verify はコンストラクタを呼び出さずに登録内容を検証します
 		Verify() error
+		// WarmUp は全てのコンポーネントを実際に解決します
+		WarmUp() error
 	}
 )
 
-// NewContainer はコンテナーを生成します
+// registrationSeq は親子のコンテナを通した登録順の採番に使用します
+var registrationSeq uint64
+
+// NewContainer はコンテナーを生成します。
+// オプションを複数指定した場合やオプションの値が正しくない場合は、全ての操作で ContainerOptionsError を返すコンテナーを生成します
 func NewContainer(options ...ContainerOptions) Container {
-	return newContainer(make(map[reflect.Type]factoryInfo), make(map[reflect.Type]reflect.Value))
+	c := newContainer(nil)
+	switch {
+	case len(options) > 1:
+		c.err = newContainerOptionsError(ErrNoMultipleOption)
+	case len(options) == 1:
+		if err := options[0].validate(); err != nil {
+			c.err = newContainerOptionsError(err)
+		}
+		c.options = options[0]
+	}
+	return c
 }
-func newContainer(factoryInfos map[reflect.Type]factoryInfo, cache map[reflect.Type]reflect.Value) *container {
+func newContainer(parent *container) *container {
+	var options ContainerOptions
+	var err error
+	if parent != nil {
+		options = parent.options
+		err = parent.err
+	}
 	return &container{
-		factoryInfos:                factoryInfos,
-		cache:                       cache,
+		parent:                      parent,
+		options:                     options,
+		err:                         err,
+		components:                  make(map[componentKey]componentList),
+		cache:                       make(map[*factoryInfo][]reflect.Value),
+		decorators:                  make(map[reflect.Type][]*factoryInfo),
 		containerInterfaceType:      reflect.TypeOf((*Container)(nil)).Elem(),
 		ioCContainerInterfaceType:   reflect.TypeOf((*IoCContainer)(nil)).Elem(),
 		serviceLocatorInterfaceType: reflect.TypeOf((*ServiceLocator)(nil)).Elem(),
+		scopeInterfaceType:          reflect.TypeOf((*Scope)(nil)).Elem(),
 	}
 }
 
-// CreateChildContainer は子コンテナを生成します
+// CreateChildContainer は子コンテナを生成します。
+// 子コンテナで見つからない登録は親コンテナから解決し、親コンテナで登録した ContainerManaged のインスタンスは親コンテナが生成して保持します。
+// 子コンテナでの登録は親コンテナの登録を変更せずに隠蔽します。ContainerOptions は親コンテナから引き継ぎます
 func (c *container) CreateChildContainer() Container {
-	factoryInfos := make(map[reflect.Type]factoryInfo)
-	for key, value := range c.factoryInfos {
-		factoryInfos[key] = value
-	}
-	cache := make(map[reflect.Type]reflect.Value)
-	for key, value := range c.cache {
-		cache[key] = value
-	}
-	return newContainer(factoryInfos, cache)
+	return newContainer(c)
 }
 
-// Register はコンストラクタまたは定数を登録します
+// Register はコンストラクタ、定数または構造体のタイプを登録します。
+// 構造体のタイプ、またはコンストラクタが返す構造体のうち dijct タグを指定したフィールドにはコンテナから注入します。
+// コンストラクタが複数の値を返す場合は、それぞれの返り値を1回の呼び出しで生成されるコンポーネントとして登録します。
+// In を埋め込んだ構造体の引数はフィールドごとに解決し、Out を埋め込んだ構造体の返り値はフィールドごとに登録します。
+// StrictRegistration の場合、このコンテナで既に登録したタイプを Multiple を指定せずに登録すると失敗します
 func (c *container) Register(target Target, options ...RegisterOptions) error {
+	return c.register(target, false, options)
+}
+
+// Replace は既存の登録を置き換えます。StrictRegistration の場合も使用でき、置き換えた登録のキャッシュを破棄します。
+// 登録するタイプのいずれも親コンテナを含めて登録されていない場合は失敗し、Multiple は指定できません
+func (c *container) Replace(target Target, options ...RegisterOptions) error {
+	return c.register(target, true, options)
+}
+func (c *container) register(target Target, replace bool, options []RegisterOptions) error {
+	if c.err != nil {
+		return newRegistrationError(reflect.TypeOf(target), c.err)
+	}
 	if len(options) > 1 {
-		return ErrNoMultipleOption
+		return newRegistrationError(reflect.TypeOf(target), ErrNoMultipleOption)
 	}
-	out, ins, err := getTargetReflectionInfos(target)
-	if err != nil {
-		return err
+	if replace && len(options) == 1 && options[0].Multiple {
+		return newRegistrationError(reflect.TypeOf(target), ErrMultipleReplace)
 	}
-	lts := InvokeManaged
-	kind := out.Kind()
+	var outs, ins []reflect.Type
+	var err error
+	value := reflect.ValueOf(target)
+	if len(options) == 1 && options[0].constant != nil {
+		if target == nil {
+			value = reflect.Zero(options[0].constant)
+		}
+		outs = []reflect.Type{value.Type()}
+	} else if target == nil {
+		return newRegistrationError(nil, ErrRequireFunction)
+	} else {
+		target = toConstructor(target)
+		if outs, ins, err = getTargetReflectionInfos(target); err != nil {
+			return newRegistrationError(reflect.TypeOf(target), err)
+		}
+		value = reflect.ValueOf(target)
+	}
+	lts := c.options.defaultLifetimeScope()
 	isFunc := ins != nil
 	if !isFunc {
 		lts = ContainerManaged
 	}
-	count := 0
-	value := reflect.ValueOf(target)
+	var interfaces []reflect.Type
+	var disposer func(interface{}) error
+	name := ""
+	multiple := false
+	unexported := false
+	var as *binding
 	if len(options) == 1 {
 		option := options[0]
-		if isFunc {
+		if isFunc && option.LifetimeScope != unspecifiedLifetimeScope {
 			lts = option.LifetimeScope
 		}
-		if option.Interfaces != nil && len(option.Interfaces) > 0 {
-			for _, p := range option.Interfaces {
-				c.factoryInfos[p] = factoryInfo{target: value, lifetimeScope: lts, ins: ins, isFunc: isFunc}
-				_, ok := c.cache[p]
-				if ok {
-					delete(c.cache, p)
-				}
-				count++
+		interfaces = option.Interfaces
+		name = option.Name
+		multiple = option.Multiple
+		disposer = option.Disposer
+		unexported = option.UnexportedFields
+		as = option.as
+	}
+	cleanup := isFunc && hasCleanup(reflect.TypeOf(target), outs)
+	var params []parameter
+	var results []result
+	var fields []fieldInjection
+	names := make([]string, len(outs))
+	if isFunc {
+		if params, err = getParameters(ins); err != nil {
+			return newRegistrationError(reflect.TypeOf(target), err)
+		}
+		if outs, names, results, err = getResults(outs); err != nil {
+			return newRegistrationError(reflect.TypeOf(target), err)
+		}
+		if fields, err = getFieldInjections(outs, unexported); err != nil {
+			return newRegistrationError(outs[0], err)
+		}
+	}
+
+	bindings := make(map[componentKey]int)
+	bound := make([]bool, len(outs))
+	for i, out := range outs {
+		key := componentKey{t: out, name: name}
+		if names[i] != "" {
+			key.name = names[i]
+		}
+		if _, ok := bindings[key]; !ok && out.Kind() != reflect.Ptr {
+			bindings[key] = i
+			bound[i] = true
+		}
+	}
+	for _, p := range interfaces {
+		i := indexOfAssignable(outs, p)
+		if i < 0 {
+			return newRegistrationError(outs[0], fmt.Errorf("%w。(%v)", ErrNotAssignable, p))
+		}
+		bindings[componentKey{t: p, name: name}] = i
+		bound[i] = true
+	}
+	if as != nil {
+		i := indexOf(outs, as.t)
+		if i < 0 {
+			return newRegistrationError(outs[0], fmt.Errorf("%w。(%v)", ErrNotAssignable, as.t))
+		}
+		if !as.t.AssignableTo(as.i) {
+			return newRegistrationError(as.t, fmt.Errorf("%w。(%v)", ErrNotAssignable, as.i))
+		}
+		bindings[componentKey{t: as.i, name: name}] = i
+		bound[i] = true
+	}
+	for i, out := range outs {
+		if !bound[i] && (as == nil || out.Kind() != reflect.Ptr) {
+			return newRegistrationError(out, ErrNeedInterfaceOnPointerRegistering)
+		}
+	}
+
+	f := &factoryInfo{
+		target:        value,
+		lifetimeScope: lts,
+		params:        params,
+		results:       results,
+		outs:          outs,
+		isFunc:        isFunc,
+		hasCleanup:    cleanup,
+		fields:        fields,
+		disposer:      disposer,
+	}
+	if c.options.StrictLifetime {
+		for key, i := range bindings {
+			if err := c.verifyCaptiveRegistration(key, f); err != nil {
+				return newRegistrationError(outs[i], err)
+			}
+		}
+	}
+	// 置き換える親コンテナの登録は、親コンテナのロックを取得するためこのコンテナのロックの前に取得します
+	inherited := make(map[componentKey][]component)
+	if replace && c.parent != nil {
+		for key := range bindings {
+			inherited[key] = c.parent.getComponentsByKey(key)
+		}
+	}
+	seq := atomic.AddUint64(&registrationSeq, 1)
+	var events []Event
+	defer func() {
+		c.emit(events...)
+	}()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	replaced := make(map[componentKey][]component)
+	for key, i := range bindings {
+		list, ok := c.components[key]
+		if c.options.StrictRegistration && !replace && !multiple && ok {
+			return newRegistrationError(outs[i], fmt.Errorf("%w。(%v)", ErrDuplicateRegistration, key))
+		}
+		if replace {
+			if !ok || list.extends {
+				replaced[key] = inherited[key]
 			}
+			replaced[key] = append(replaced[key][:len(replaced[key]):len(replaced[key])], list.components...)
+		}
+	}
+	if replace {
+		found := false
+		for _, cmps := range replaced {
+			found = found || len(cmps) > 0
+		}
+		if !found {
+			return newRegistrationError(outs[0], ErrNotRegisteredComponent)
 		}
+		f.replaced = replaced
+		c.decorations.reset()
 	}
-	if kind != reflect.Ptr {
-		c.factoryInfos[out] = factoryInfo{target: value, lifetimeScope: lts, ins: ins, isFunc: isFunc}
-		_, ok := c.cache[out]
-		if ok {
-			delete(c.cache, out)
+	for key, i := range bindings {
+		cmp := component{factoryInfo: f, index: i, seq: seq, owner: c}
+		kind := EventRegistered
+		if replace {
+			kind = EventReplaced
+		}
+		events = append(events, newEvent(kind, key, f))
+		if multiple {
+			list, ok := c.components[key]
+			if !ok {
+				list.extends = true
+			}
+			list.components = append(list.components[:len(list.components):len(list.components)], cmp)
+			c.components[key] = list
+			continue
+		}
+		old := c.components[key]
+		c.components[key] = componentList{components: []component{cmp}}
+		for _, cmp := range old.components {
+			if !c.isRegistered(cmp.factoryInfo) {
+				delete(c.cache, cmp.factoryInfo)
+			}
 		}
-		count++
-	} else if count == 0 {
-		return ErrNeedInterfaceOnPointerRegistering
 	}
 	return nil
 }
+func indexOfAssignable(outs []reflect.Type, t reflect.Type) int {
+	for i, out := range outs {
+		if out.AssignableTo(t) {
+			return i
+		}
+	}
+	return -1
+}
 
-// Invoke はコンテナからインスタンスを解決して呼び出します
+// isRegistered は factoryInfo がいずれかのタイプで登録されているかを返します。呼び出し元でロックしてください
+func (c *container) isRegistered(f *factoryInfo) bool {
+	for _, list := range c.components {
+		for _, cmp := range list.components {
+			if cmp.factoryInfo == f {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Invoke はコンテナからインスタンスを解決して呼び出します。
+// 呼び出しのために生成した InvokeManaged のインスタンスは呼び出しの終了時に破棄します
 func (c *container) Invoke(invoker Invoker) error {
+	return c.InvokeContext(context.Background(), invoker)
+}
+
+// InvokeContext は ctx を context.Context として解決できる状態で Invoke します。
+// ctx がキャンセルされた場合は以降のコンストラクタを呼び出さずに ctx.Err() を返します
+func (c *container) InvokeContext(ctx context.Context, invoker Invoker) error {
+	return c.invoke(newResolveContext(ctx, nil), invoker)
+}
+func (c *container) invoke(rc *resolveContext, invoker Invoker) (err error) {
+	if c.err != nil {
+		return c.err
+	}
 	t := reflect.TypeOf(invoker)
 	if t.Kind() != reflect.Func {
 		return ErrRequireFunction
 	}
 	ins := getIns(t)
-	lenIns := len(ins)
-	if lenIns == 0 {
+	if len(ins) == 0 {
 		return ErrNotFoundComponent
 	}
-	args := make([]reflect.Value, lenIns)
-	cache := make(map[reflect.Type]reflect.Value)
-	for i, in := range ins {
-		v, err := c.resolve(in, &cache)
-		if err != nil {
-			return err
+	params, err := getParameters(ins)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if _, e := disposeAll(context.Background(), rc.invocation.close()); e != nil && err == nil {
+			err = e
 		}
-		args[i] = *v
+	}()
+	values, err := c.resolveDependencies(dependenciesOf(params), rc)
+	if err != nil {
+		return err
+	}
+	if err := rc.ctx.Err(); err != nil {
+		return err
 	}
 
 	fn := reflect.ValueOf(invoker)
-	outs := fn.Call(args)
-	if err := c.getError(outs); err != nil {
+	outs := fn.Call(buildArgs(params, values))
+	if err := getError(outs); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (c *container) getError(outs []reflect.Value) error {
-	l := len(outs)
-	if l > 0 {
-		if err, ok := outs[l-1].Interface().(error); ok {
-			return err
-		}
+// ResolveNamed は名前付きで登録されたコンポーネントを解決します。name が空の場合は名前なしの登録を解決します。
+// 解決のために生成した InvokeManaged のインスタンスは破棄しないため、呼び出し元で破棄してください
+func (c *container) ResolveNamed(t reflect.Type, name string) (interface{}, error) {
+	return c.resolveNamed(newResolveContext(context.Background(), nil), t, name)
+}
+func (c *container) resolveNamed(rc *resolveContext, t reflect.Type, name string) (interface{}, error) {
+	if c.err != nil {
+		return nil, c.err
 	}
-	return nil
+	v, err := c.resolve(componentKey{t: t, name: name}, rc)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
 }
 
-func (c *container) resolve(t reflect.Type, cache *map[reflect.Type]reflect.Value) (*reflect.Value, error) {
-	if c.containerInterfaceType == t || c.ioCContainerInterfaceType == t || c.serviceLocatorInterfaceType == t {
+func (c *container) resolve(key componentKey, rc *resolveContext) (*reflect.Value, error) {
+	if key.name == "" && !c.options.DisableAutoInjection && rc.scope != nil && (c.serviceLocatorInterfaceType == key.t || c.scopeInterfaceType == key.t) {
+		v := reflect.ValueOf(rc.scope)
+		return &v, nil
+	}
+	if key.name == "" && !c.options.DisableAutoInjection && (c.containerInterfaceType == key.t || c.ioCContainerInterfaceType == key.t || c.serviceLocatorInterfaceType == key.t) {
 		v := reflect.ValueOf(c)
 		return &v, nil
 	}
-	factoryInfo, ok := c.factoryInfos[t]
+	if key.name == "" && key.t == contextType {
+		v := reflect.ValueOf(&rc.ctx).Elem()
+		return &v, nil
+	}
+	cmp, ok := c.getComponent(key)
 	if !ok {
-		return nil, newErrInvalidResolveComponent(t)
+		if elem, ok := unwrapOptional(key); ok {
+			return c.resolveOptional(key, elem, rc)
+		}
+		if elem, ok := unwrapDeferred(key); ok {
+			return c.resolveDeferred(key, elem, rc)
+		}
+		if v, ok, err := c.resolveCollection(key, rc); ok {
+			return v, err
+		}
+		return nil, newResolveError(key, rc.path, ErrNotRegisteredComponent)
+	}
+	return c.resolveComponent(key, cmp, rc)
+}
+func (c *container) resolveComponent(key componentKey, cmp component, rc *resolveContext) (*reflect.Value, error) {
+	if i := rc.resolving(cmp.factoryInfo); i >= 0 {
+		return nil, newCircularDependencyError(rc.path[i:], key)
+	}
+	if n := len(rc.factoryInfos); c.options.StrictLifetime && n > 0 && isCaptive(rc.factoryInfos[n-1], cmp.factoryInfo) {
+		return nil, newCaptiveDependencyError(rc.path[n-1], rc.factoryInfos[n-1], key, cmp.factoryInfo)
 	}
-	switch factoryInfo.lifetimeScope {
-	case ContainerManaged:
-		return c.resolveContainerManagedObject(t, factoryInfo, cache)
+	rc.push(key, cmp.factoryInfo)
+	defer rc.pop()
+	var outs []reflect.Value
+	var err error
+	switch {
+	case cmp.factoryInfo.lifetimeScope == ContainerManaged:
+		outs, err = cmp.owner.resolveContainerManagedObject(key, cmp.factoryInfo, rc)
+	case cmp.factoryInfo.lifetimeScope == ScopeManaged && rc.scope != nil:
+		outs, err = rc.scope.resolveScopeManagedObject(key, cmp.factoryInfo, rc)
+	case cmp.factoryInfo.lifetimeScope == Transient:
+		outs, err = c.resolveTransientObject(key, cmp.factoryInfo, rc)
+	default:
+		outs, err = c.resolveInvokeManagedObject(key, cmp.factoryInfo, rc)
 	}
-	return c.resolveInvokeManagedObject(t, factoryInfo, cache)
+	if err != nil {
+		return nil, err
+	}
+	v, err := c.decorate(key, cmp, outs[cmp.index], rc)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
 }
-func (c *container) resolveContainerManagedObject(t reflect.Type, factoryInfo factoryInfo, cache *map[reflect.Type]reflect.Value) (*reflect.Value, error) {
-	if v, ok := c.cache[t]; ok {
-		return &v, nil
+
+// getComponent は key で最後に登録されたコンポーネントを親コンテナも含めて返します
+func (c *container) getComponent(key componentKey) (component, bool) {
+	cmps := c.getComponentsByKey(key)
+	if len(cmps) == 0 {
+		return component{}, false
 	}
-	if !factoryInfo.isFunc {
-		c.cache[t] = factoryInfo.target
-		return &factoryInfo.target, nil
+	return cmps[len(cmps)-1], true
+}
+
+// getComponentsByKey は key で登録された全てのコンポーネントを親コンテナも含めて登録順に返します
+func (c *container) getComponentsByKey(key componentKey) []component {
+	c.mu.RLock()
+	list, ok := c.components[key]
+	c.mu.RUnlock()
+	if c.parent == nil || (ok && !list.extends) {
+		return list.components
 	}
-	lenIns := len(factoryInfo.ins)
-	args := make([]reflect.Value, lenIns)
-	for i, in := range factoryInfo.ins {
-		v, err := c.resolve(in, cache)
-		if err != nil {
-			return nil, err
-		}
-		args[i] = *v
+	inherited := c.parent.getComponentsByKey(key)
+	if len(list.components) == 0 {
+		return inherited
 	}
+	return append(inherited[:len(inherited):len(inherited)], list.components...)
+}
 
-	outs := factoryInfo.target.Call(args)
-	for _, out := range outs {
-		if err, ok := out.Interface().(error); ok {
-			return nil, err
-		}
+// getKeys は親コンテナも含めて登録された全てのキーを返します
+func (c *container) getKeys() map[componentKey]struct{} {
+	keys := make(map[componentKey]struct{})
+	if c.parent != nil {
+		keys = c.parent.getKeys()
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for key := range c.components {
+		keys[key] = struct{}{}
+	}
+	return keys
+}
+func (c *container) getCache(f *factoryInfo) ([]reflect.Value, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.cache[f]
+	return v, ok
+}
+
+// setCache は生成中に登録が解除されていない場合のみキャッシュします。破棄が必要なインスタンスはキャッシュの有無に関わらず記録します
+func (c *container) setCache(f *factoryInfo, v []reflect.Value, ds []disposable) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.isRegistered(f) {
+		c.cache[f] = v
 	}
-	if err := c.getError(outs); err != nil {
+	c.disposables = append(c.disposables, ds...)
+}
+func (c *container) resolveContainerManagedObject(key componentKey, factoryInfo *factoryInfo, rc *resolveContext) ([]reflect.Value, error) {
+	if v, ok := c.getCache(factoryInfo); ok {
+		return v, nil
+	}
+
+	// 同一のコンストラクタが並行して呼ばれないよう、生成は factoryInfo ごとに直列化します
+	if err := rc.lock(&factoryInfo.mu); err != nil {
 		return nil, err
 	}
-	out := outs[0]
-	c.cache[t] = out
-	return &out, nil
+	defer rc.unlock(&factoryInfo.mu)
+	if v, ok := c.getCache(factoryInfo); ok {
+		return v, nil
+	}
+	outs, ds, err := c.construct(key, factoryInfo, rc)
+	if err != nil {
+		return nil, err
+	}
+	c.setCache(factoryInfo, outs, ds)
+	return outs, nil
 }
-func (c *container) resolveInvokeManagedObject(t reflect.Type, factoryInfo factoryInfo, cache *map[reflect.Type]reflect.Value) (*reflect.Value, error) {
-	cch := *cache
-	if v, ok := cch[t]; ok {
-		return &v, nil
+func (c *container) resolveInvokeManagedObject(key componentKey, factoryInfo *factoryInfo, rc *resolveContext) ([]reflect.Value, error) {
+	i := rc.invocation
+	if v, ok := i.getCache(factoryInfo); ok {
+		return v, nil
 	}
-	if !factoryInfo.isFunc {
-		cch[t] = factoryInfo.target
-		return &factoryInfo.target, nil
+	l := i.getBuildLock(factoryInfo)
+	if err := rc.lock(l); err != nil {
+		return nil, err
 	}
-	lenIns := len(factoryInfo.ins)
-	args := make([]reflect.Value, lenIns)
-	for i, in := range factoryInfo.ins {
-		v, err := c.resolve(in, cache)
-		if err != nil {
-			return nil, err
-		}
-		args[i] = *v
+	defer rc.unlock(l)
+	if v, ok := i.getCache(factoryInfo); ok {
+		return v, nil
 	}
-
-	outs := factoryInfo.target.Call(args)
-	if err := c.getError(outs); err != nil {
+	outs, ds, err := c.construct(key, factoryInfo, rc)
+	if err != nil {
 		return nil, err
 	}
-	out := outs[0]
-	cch[t] = out
-	return &out, nil
+	i.setCache(factoryInfo, outs, ds)
+	return outs, nil
 }
-
-func (c *container) Verify() error {
-	lenIns := len(c.factoryInfos)
-	if lenIns == 0 {
-		return ErrNotFoundComponent
+func (c *container) resolveTransientObject(key componentKey, factoryInfo *factoryInfo, rc *resolveContext) ([]reflect.Value, error) {
+	outs, ds, err := c.construct(key, factoryInfo, rc)
+	if err != nil {
+		return nil, err
+	}
+	rc.invocation.addDisposables(ds)
+	return outs, nil
+}
+func (c *container) construct(key componentKey, factoryInfo *factoryInfo, rc *resolveContext) ([]reflect.Value, []disposable, error) {
+	args, err := c.resolveDependencies(factoryInfo.dependencies(), rc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := rc.ctx.Err(); err != nil {
+		return nil, nil, err
 	}
-	args := make([]reflect.Value, lenIns)
-	cache := make(map[reflect.Type]reflect.Value)
-	i := 0
-	for t := range c.factoryInfos {
-		v, err := c.resolve(t, &cache)
+	start := time.Now()
+	outs, ds, err := factoryInfo.call(args)
+	if factoryInfo.isFunc {
+		e := newEvent(EventConstructed, key, factoryInfo)
+		e.Duration, e.Err = time.Since(start), err
+		rc.emit(c, e)
+	}
+	if err != nil {
+		return nil, nil, newConstructorError(key, rc.path, err)
+	}
+	return outs, ds, nil
+}
+
+// resolveDependencies は deps を順に解決します。optional で登録がない依存先は無効な値になります
+func (c *container) resolveDependencies(deps []dependency, rc *resolveContext) ([]reflect.Value, error) {
+	values := make([]reflect.Value, len(deps))
+	for i, dep := range deps {
+		if dep.optional && !c.isResolvable(dep.key, rc.scope) {
+			continue
+		}
+		v, err := c.resolve(dep.key, rc)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		args[i] = *v
-		i++
+		values[i] = *v
 	}
-	return nil
+	return values, nil
+}
+
+// Close はコンテナが生成した ContainerManaged のインスタンスを生成と逆の順序で破棄し、キャッシュを破棄します。
+// 破棄に失敗した場合も残りのインスタンスの破棄を続け、全てのエラーを DisposeError にまとめて返します。
+// ctx が終了した場合は中断し、破棄していないインスタンスは再び Close を呼び出した際に破棄します
+func (c *container) Close(ctx context.Context) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.mu.Lock()
+	ds := c.disposables
+	c.disposables = nil
+	c.cache = make(map[*factoryInfo][]reflect.Value)
+	c.mu.Unlock()
+	c.decorations.reset()
+	remaining, err := disposeAll(ctx, ds)
+	if len(remaining) > 0 {
+		c.mu.Lock()
+		c.disposables = append(remaining, c.disposables...)
+		c.mu.Unlock()
+	}
+	return err
 }
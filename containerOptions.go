@@ -1,6 +1,36 @@
 package dijct
 
+import "fmt"
+
 type (
-	// ContainerOptions はコンテナの生成オプションです
-	ContainerOptions struct{}
+	// ContainerOptions はコンテナの生成オプションです。子コンテナは親コンテナのオプションを引き継ぎます
+	ContainerOptions struct {
+		// StrictLifetime が true の場合、ライフタイムの長いコンポーネントが短いコンポーネントに依存する登録と解決を CaptiveDependencyError で失敗させます。
+		// false の場合は Verify でのみ検出します
+		StrictLifetime bool
+		// StrictRegistration が true の場合、同じコンテナで同じタイプと登録名を Multiple を指定せずに再び登録すると ErrDuplicateRegistration で失敗させます。
+		// 登録を置き換える場合は Replace を使用します
+		StrictRegistration bool
+		// DefaultLifetimeScope は RegisterOptions の LifetimeScope を指定せずにコンストラクタを登録した場合のライフタイムです。ゼロ値の場合は InvokeManaged です
+		DefaultLifetimeScope LifetimeScope
+		// DisableAutoInjection が true の場合、Container、IoCContainer、ServiceLocator と Scope を登録せずに注入しません
+		DisableAutoInjection bool
+		// Hook を指定すると、登録とコンストラクタの呼び出しを Event で通知します。ログの出力などに使用します
+		Hook func(event Event)
+	}
 )
+
+func (o ContainerOptions) defaultLifetimeScope() LifetimeScope {
+	if o.DefaultLifetimeScope == unspecifiedLifetimeScope {
+		return InvokeManaged
+	}
+	return o.DefaultLifetimeScope
+}
+
+// validate はオプションの値が正しいかを検証します
+func (o ContainerOptions) validate() error {
+	if o.DefaultLifetimeScope != unspecifiedLifetimeScope && !o.DefaultLifetimeScope.valid() {
+		return fmt.Errorf("%w。(%v)", ErrInvalidLifetimeScope, o.DefaultLifetimeScope)
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package dijct
+
+import (
+	"reflect"
+	"sync"
+)
+
+type (
+	// decoratedKey はデコレーターを適用した値をキャッシュするキーです
+	decoratedKey struct {
+		factoryInfo *factoryInfo
+		index       int
+		t           reflect.Type
+	}
+	// decoratedValue はデコレーターを適用した値と、適用した時点の stamp です
+	decoratedValue struct {
+		value reflect.Value
+		stamp []uint64
+	}
+	// decorations はデコレーターを適用した値のキャッシュです。ゼロ値で使用できます
+	decorations struct {
+		mu     sync.Mutex
+		values map[decoratedKey]decoratedValue
+		locks  map[decoratedKey]*sync.Mutex
+		// generation は reset の度に増加します。他のコンテナのキャッシュは適用した時点の generation と比較して破棄します
+		generation uint64
+	}
+	// decoratorGroup は同じ場所にキャッシュするデコレーターです
+	decoratorGroup struct {
+		// container はデコレーターの依存先を解決するコンテナです
+		container *container
+		// holder は適用した値のキャッシュです。nil の場合はキャッシュしません
+		holder     *decorations
+		decorators []*factoryInfo
+		// sources はデコレーターを登録したコンテナです
+		sources []*container
+	}
+)
+
+// Decorate は func(T, deps...) T または func(T, deps...) (T, error) のデコレーターを登録します。
+// 解決される全ての T に登録順に重ねて適用し、デコレーターの依存先はコンテナから解決します。
+// 子コンテナで登録したデコレーターはその子コンテナから解決した場合のみ適用します
+func (c *container) Decorate(decorator Target) error {
+	t := reflect.TypeOf(decorator)
+	if c.err != nil {
+		return newRegistrationError(t, c.err)
+	}
+	if t == nil || t.Kind() != reflect.Func {
+		return newRegistrationError(t, ErrRequireFunction)
+	}
+	outs, err := getOuts(t)
+	if err != nil {
+		return newRegistrationError(t, err)
+	}
+	if len(outs) != 1 || hasCleanup(t, outs) || t.NumIn() == 0 || t.In(0) != outs[0] {
+		return newRegistrationError(t, ErrInvalidDecorator)
+	}
+	params, err := getParameters(getIns(t))
+	if err != nil {
+		return newRegistrationError(t, err)
+	}
+	if params[0].fields != nil {
+		return newRegistrationError(t, ErrInvalidDecorator)
+	}
+	f := &factoryInfo{
+		target:  reflect.ValueOf(decorator),
+		params:  params,
+		results: []result{{t: outs[0]}},
+		outs:    outs,
+		isFunc:  true,
+	}
+	c.mu.Lock()
+	ds := c.decorators[outs[0]]
+	c.decorators[outs[0]] = append(ds[:len(ds):len(ds)], f)
+	c.mu.Unlock()
+	c.decorations.reset()
+	return nil
+}
+
+// getDecoratorGroups は t のデコレーターを親コンテナから順にコンテナごとに返します
+func (c *container) getDecoratorGroups(t reflect.Type) []decoratorGroup {
+	var groups []decoratorGroup
+	if c.parent != nil {
+		groups = c.parent.getDecoratorGroups(t)
+	}
+	c.mu.RLock()
+	ds := c.decorators[t]
+	c.mu.RUnlock()
+	if len(ds) > 0 {
+		groups = append(groups, decoratorGroup{container: c, holder: &c.decorations, decorators: ds, sources: []*container{c}})
+	}
+	return groups
+}
+
+// getDecoratorDependencies は t のデコレーターの依存先を返します
+func (c *container) getDecoratorDependencies(t reflect.Type) []dependency {
+	var deps []dependency
+	for _, g := range c.getDecoratorGroups(t) {
+		for _, d := range g.decorators {
+			deps = append(deps, d.dependencies()[1:]...)
+		}
+	}
+	return deps
+}
+
+// hasAncestor は a が c 自身または c の親コンテナかを返します
+func (c *container) hasAncestor(a *container) bool {
+	for p := c; p != nil; p = p.parent {
+		if p == a {
+			return true
+		}
+	}
+	return false
+}
+
+// decorate は key として解決した v にデコレーターを適用します。
+// デコレーターを適用した値は cmp のライフタイムに従ってキャッシュします。
+// ContainerManaged の場合、owner とその親コンテナのデコレーターは owner で、owner の子コンテナのデコレーターはそれぞれのコンテナでキャッシュします。
+// キャッシュした値は、v を生成した owner と先に適用したデコレーターのコンテナのいずれかがキャッシュを破棄した場合に適用し直します
+func (c *container) decorate(key componentKey, cmp component, v reflect.Value, rc *resolveContext) (reflect.Value, error) {
+	groups := c.getDecoratorGroups(key.t)
+	if len(groups) == 0 {
+		return v, nil
+	}
+	all := decoratorGroup{container: c}
+	for _, g := range groups {
+		all.decorators = append(all.decorators, g.decorators...)
+		all.sources = append(all.sources, g.sources...)
+	}
+	var stamp []uint64
+	switch {
+	case cmp.factoryInfo.lifetimeScope == ContainerManaged:
+		stamp = append(stamp, cmp.owner.decorations.getGeneration())
+		owner := decoratorGroup{container: cmp.owner, holder: &cmp.owner.decorations}
+		var rest []decoratorGroup
+		for _, g := range groups {
+			if cmp.owner.hasAncestor(g.container) {
+				owner.decorators = append(owner.decorators, g.decorators...)
+				owner.sources = append(owner.sources, g.sources...)
+			} else {
+				rest = append(rest, g)
+			}
+		}
+		groups = rest
+		if len(owner.decorators) > 0 {
+			groups = append([]decoratorGroup{owner}, rest...)
+		}
+	case cmp.factoryInfo.lifetimeScope == ScopeManaged && rc.scope != nil:
+		all.holder = &rc.scope.decorations
+		groups = []decoratorGroup{all}
+	case cmp.factoryInfo.lifetimeScope == Transient:
+		groups = []decoratorGroup{all}
+	default:
+		all.holder = &rc.invocation.decorations
+		groups = []decoratorGroup{all}
+	}
+	dk := decoratedKey{factoryInfo: cmp.factoryInfo, index: cmp.index, t: key.t}
+	for _, g := range groups {
+		for _, s := range g.sources {
+			stamp = append(stamp, s.decorations.getGeneration())
+		}
+		var err error
+		if v, err = g.apply(dk, stamp, key, v, rc); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return v, nil
+}
+
+// apply は g のデコレーターを適用します。stamp は v を生成したコンテナと g までのデコレーターのコンテナの generation です
+func (g decoratorGroup) apply(dk decoratedKey, stamp []uint64, key componentKey, v reflect.Value, rc *resolveContext) (reflect.Value, error) {
+	if g.holder == nil {
+		return g.container.applyDecorators(g.decorators, key, v, rc)
+	}
+	if cached, ok := g.holder.get(dk, stamp); ok {
+		return cached, nil
+	}
+	l := g.holder.lock(dk)
+	if err := rc.lock(l); err != nil {
+		return reflect.Value{}, err
+	}
+	defer rc.unlock(l)
+	if cached, ok := g.holder.get(dk, stamp); ok {
+		return cached, nil
+	}
+	v, err := g.container.applyDecorators(g.decorators, key, v, rc)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	g.holder.set(dk, stamp, v)
+	return v, nil
+}
+
+// applyDecorators はデコレーターを順に適用します。デコレーターが返した値は破棄の対象にしません
+func (c *container) applyDecorators(decorators []*factoryInfo, key componentKey, v reflect.Value, rc *resolveContext) (reflect.Value, error) {
+	for _, d := range decorators {
+		deps, err := c.resolveDependencies(d.dependencies()[1:], rc)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if err := rc.ctx.Err(); err != nil {
+			return reflect.Value{}, err
+		}
+		outs, _, err := d.call(append([]reflect.Value{v}, deps...))
+		if err != nil {
+			return reflect.Value{}, newConstructorError(key, rc.path, err)
+		}
+		v = outs[0]
+	}
+	return v, nil
+}
+
+// get は stamp が適用した時点から変わっていない場合のみキャッシュした値を返します
+func (d *decorations) get(key decoratedKey, stamp []uint64) (reflect.Value, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	v, ok := d.values[key]
+	if !ok || len(v.stamp) != len(stamp) {
+		return reflect.Value{}, false
+	}
+	for i := range stamp {
+		if v.stamp[i] != stamp[i] {
+			return reflect.Value{}, false
+		}
+	}
+	return v.value, true
+}
+func (d *decorations) set(key decoratedKey, stamp []uint64, v reflect.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.values == nil {
+		d.values = make(map[decoratedKey]decoratedValue)
+	}
+	d.values[key] = decoratedValue{value: v, stamp: append([]uint64{}, stamp...)}
+}
+func (d *decorations) getGeneration() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.generation
+}
+func (d *decorations) lock(key decoratedKey) *sync.Mutex {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.locks == nil {
+		d.locks = make(map[decoratedKey]*sync.Mutex)
+	}
+	l, ok := d.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		d.locks[key] = l
+	}
+	return l
+}
+
+// reset はキャッシュを破棄し、generation を進めて他のコンテナのキャッシュを無効にします
+func (d *decorations) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.values = nil
+	d.generation++
+}
@@ -0,0 +1,63 @@
+package dijct
+
+import (
+	"context"
+	"io"
+	"reflect"
+)
+
+type (
+	// disposable はコンテナが生成し、スコープの終了時に破棄するインスタンスです
+	disposable struct {
+		value    reflect.Value
+		disposer func(instance interface{}) error
+		cleanup  func()
+	}
+)
+
+// newDisposables はコンストラクタで生成された返り値のうち破棄が必要なものを返します。
+// コンストラクタがクリーンアップ関数を返した場合は io.Closer の代わりにクリーンアップ関数で破棄します
+func newDisposables(f *factoryInfo, outs []reflect.Value, cleanup func()) []disposable {
+	var ds []disposable
+	for _, out := range outs {
+		if f.disposer != nil {
+			ds = append(ds, disposable{value: out, disposer: f.disposer})
+			continue
+		}
+		if _, ok := out.Interface().(io.Closer); ok && cleanup == nil {
+			ds = append(ds, disposable{value: out})
+		}
+	}
+	if cleanup != nil {
+		ds = append(ds, disposable{cleanup: cleanup})
+	}
+	return ds
+}
+func (d disposable) dispose() error {
+	if d.cleanup != nil {
+		d.cleanup()
+		return nil
+	}
+	if d.disposer != nil {
+		return d.disposer(d.value.Interface())
+	}
+	return d.value.Interface().(io.Closer).Close()
+}
+
+// disposeAll は生成とは逆の順序で破棄し、発生した全てのエラーをまとめて返します。
+// ctx が終了した場合は中断し、破棄していないインスタンスを生成順に返します
+func disposeAll(ctx context.Context, ds []disposable) ([]disposable, error) {
+	var errs []error
+	for i := len(ds) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			return ds[:i+1], &DisposeError{Errs: append(errs, err)}
+		}
+		if err := ds[i].dispose(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &DisposeError{Errs: errs}
+	}
+	return nil, nil
+}
@@ -1,6 +1,7 @@
 package dijct
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -12,11 +13,237 @@ var (
 	ErrRequireFunction                   = fmt.Errorf("関数を指定してください")
 	ErrNotFoundComponent                 = fmt.Errorf("解決するオブジェクトが存在しません")
 	ErrRequireResponse                   = fmt.Errorf("登録する関数には返り値が必要です")
+	ErrNotRegisteredComponent            = fmt.Errorf("コンポーネントが登録されていません")
+	ErrNotAssignable                     = fmt.Errorf("登録するタイプに代入できません")
+	ErrUnexportedField                   = fmt.Errorf("非公開のフィールドに注入する場合は、UnexportedFields を指定する必要があります")
+	ErrInvalidTag                        = fmt.Errorf("タグの指定が正しくありません")
+	ErrNilStruct                         = fmt.Errorf("フィールドに注入する構造体のポインタが nil です")
+	ErrUnexportedParameterField          = fmt.Errorf("In または Out を埋め込んだ構造体のフィールドは公開されている必要があります")
+	ErrInvalidDecorator                  = fmt.Errorf("デコレーターは func(T, ...) T の形式である必要があります")
+	ErrDuplicateRegistration             = fmt.Errorf("既に登録されています。置き換える場合は Replace を使用してください")
+	ErrMultipleReplace                   = fmt.Errorf("Replace では Multiple を指定できません")
+	ErrInvalidLifetimeScope              = fmt.Errorf("ライフタイムスコープが正しくありません")
 )
 
-func newErrInvalidResolveComponent(t reflect.Type) error {
-	return fmt.Errorf("指定されたタイプを解決できません。(%v)", t)
+type (
+	// ResolveError はコンポーネントを解決できなかった場合のエラーです
+	ResolveError struct {
+		// Type は解決できなかったタイプです
+		Type reflect.Type
+		// Name は解決できなかったコンポーネントの登録名です
+		Name string
+		// Path は Invoke から Type に至るまでに解決したタイプの順序です。末尾は Type になります
+		Path []reflect.Type
+		Err  error
+		keys []componentKey
+	}
+	// ConstructorError はコンストラクタがエラーを返した場合のエラーです
+	ConstructorError struct {
+		// Type はコンストラクタで生成しようとしたタイプです
+		Type reflect.Type
+		// Name はコンストラクタで生成しようとしたコンポーネントの登録名です
+		Name string
+		// Path は Invoke から Type に至るまでに解決したタイプの順序です。末尾は Type になります
+		Path []reflect.Type
+		Err  error
+		keys []componentKey
+	}
+	// RegistrationError はコンポーネントを登録できなかった場合のエラーです
+	RegistrationError struct {
+		// Type は登録しようとしたタイプです
+		Type reflect.Type
+		Err  error
+	}
+	// ContainerOptionsError はコンテナのオプションが正しくない場合に、コンテナの全ての操作が返すエラーです
+	ContainerOptionsError struct {
+		Err error
+	}
+	// DisposeError はインスタンスの破棄中に発生したエラーです
+	DisposeError struct {
+		// Errs は破棄した順に発生したエラーです
+		Errs []error
+	}
+	// VerificationReport は Verify で検出した全ての問題です
+	VerificationReport struct {
+		entries []VerificationEntry
+	}
+	// VerificationEntry は Verify で検出した問題の1件です
+	VerificationEntry struct {
+		// Type は解決できないタイプです
+		Type reflect.Type
+		// Name は解決できないコンポーネントの登録名です
+		Name string
+		// Consumer は Type を必要としたタイプです
+		Consumer reflect.Type
+		// ConsumerName は Type を必要としたコンポーネントの登録名です
+		ConsumerName string
+		// Err は ResolveError、CircularDependencyError、CaptiveDependencyError のいずれかです
+		Err error
+	}
+	// CircularDependencyError は依存関係が循環している場合のエラーです
+	CircularDependencyError struct {
+		// Path は循環を検出するまでに解決したタイプの順序です。先頭と末尾は同じタイプになります
+		Path []reflect.Type
+		keys []componentKey
+	}
+	// CaptiveDependencyError はライフタイムの長いコンポーネントが短いコンポーネントに依存している場合のエラーです
+	CaptiveDependencyError struct {
+		// Type は依存しているタイプです
+		Type reflect.Type
+		// Name は依存しているコンポーネントの登録名です
+		Name          string
+		LifetimeScope LifetimeScope
+		// Dependency は依存先のタイプです
+		Dependency reflect.Type
+		// DependencyName は依存先のコンポーネントの登録名です
+		DependencyName          string
+		DependencyLifetimeScope LifetimeScope
+	}
+)
+
+func newResolveError(key componentKey, path []componentKey, err error) error {
+	keys := appendPath(path, key)
+	return &ResolveError{Type: key.t, Name: key.name, Path: toTypes(keys), Err: err, keys: keys}
+}
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf("指定されたタイプを解決できません。(%s): %v", formatPath(e.keys), e.Err)
+}
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+func newConstructorError(key componentKey, path []componentKey, err error) error {
+	keys := appendPath(path, key)
+	return &ConstructorError{Type: key.t, Name: key.name, Path: toTypes(keys), Err: err, keys: keys}
+}
+func (e *ConstructorError) Error() string {
+	return fmt.Sprintf("コンストラクタがエラーを返しました。(%s): %v", formatPath(e.keys), e.Err)
+}
+func (e *ConstructorError) Unwrap() error {
+	return e.Err
+}
+
+func newRegistrationError(t reflect.Type, err error) error {
+	return &RegistrationError{Type: t, Err: err}
 }
+func (e *RegistrationError) Error() string {
+	return fmt.Sprintf("登録できません。(%v): %v", e.Type, e.Err)
+}
+func (e *RegistrationError) Unwrap() error {
+	return e.Err
+}
+
+func newContainerOptionsError(err error) error {
+	return &ContainerOptionsError{Err: err}
+}
+func (e *ContainerOptionsError) Error() string {
+	return fmt.Sprintf("コンテナのオプションが正しくありません。: %v", e.Err)
+}
+func (e *ContainerOptionsError) Unwrap() error {
+	return e.Err
+}
+
+// newCircularDependencyError は循環の始点から key までの path で生成します
+func newCircularDependencyError(path []componentKey, key componentKey) error {
+	keys := make([]componentKey, 0, len(path)+1)
+	keys = append(keys, path...)
+	keys = append(keys, key)
+	return &CircularDependencyError{Path: toTypes(keys), keys: keys}
+}
+func (e *CircularDependencyError) Error() string {
+	return fmt.Sprintf("依存関係が循環しています。(%s)", formatPath(e.keys))
+}
+
+func newCaptiveDependencyError(key componentKey, f *factoryInfo, dep componentKey, depFactory *factoryInfo) error {
+	return &CaptiveDependencyError{
+		Type:                    key.t,
+		Name:                    key.name,
+		LifetimeScope:           f.lifetimeScope,
+		Dependency:              dep.t,
+		DependencyName:          dep.name,
+		DependencyLifetimeScope: depFactory.lifetimeScope,
+	}
+}
+func (e *CaptiveDependencyError) Error() string {
+	key := componentKey{t: e.Type, name: e.Name}
+	dep := componentKey{t: e.Dependency, name: e.DependencyName}
+	return fmt.Sprintf("ライフタイムの短いコンポーネントに依存しています。(%v(%v) -> %v(%v))", key, e.LifetimeScope, dep, e.DependencyLifetimeScope)
+}
+
+func (e *DisposeError) Error() string {
+	messages := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("インスタンスの破棄に失敗しました。: %s", strings.Join(messages, ", "))
+}
+func (e *DisposeError) Unwrap() []error {
+	return e.Errs
+}
+
+// Entries は検出した順の問題です
+func (r *VerificationReport) Entries() []VerificationEntry {
+	entries := make([]VerificationEntry, len(r.entries))
+	copy(entries, r.entries)
+	return entries
+}
+func (r *VerificationReport) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "登録内容の検証に失敗しました。(%d件)", len(r.entries))
+	for _, entry := range r.entries {
+		fmt.Fprintf(&b, "\n  - %v", entry)
+	}
+	return b.String()
+}
+func (r *VerificationReport) Unwrap() []error {
+	errs := make([]error, len(r.entries))
+	for i, entry := range r.entries {
+		errs[i] = entry.Err
+	}
+	return errs
+}
+func (e VerificationEntry) String() string {
+	consumer := componentKey{t: e.Consumer, name: e.ConsumerName}
+	return fmt.Sprintf("%v が必要とする %v: %v", consumer, componentKey{t: e.Type, name: e.Name}, e.Err)
+}
+
+// IsErrInvalidResolveComponent は登録されていないタイプを解決しようとしたエラーかどうかを判定します
 func IsErrInvalidResolveComponent(err error) bool {
-	return strings.HasPrefix(err.Error(), "指定されたタイプを解決できません。")
+	return errors.Is(err, ErrNotRegisteredComponent)
+}
+
+// IsErrCircularDependency は依存関係の循環によるエラーかどうかを判定します
+func IsErrCircularDependency(err error) bool {
+	var e *CircularDependencyError
+	return errors.As(err, &e)
+}
+
+// IsErrCaptiveDependency はライフタイムの長いコンポーネントが短いコンポーネントに依存していることによるエラーかどうかを判定します
+func IsErrCaptiveDependency(err error) bool {
+	var e *CaptiveDependencyError
+	return errors.As(err, &e)
+}
+
+// appendPath は path を共有しないよう複製して key を末尾に追加します
+func appendPath(path []componentKey, key componentKey) []componentKey {
+	p := make([]componentKey, len(path), len(path)+1)
+	copy(p, path)
+	if len(p) == 0 || p[len(p)-1] != key {
+		p = append(p, key)
+	}
+	return p
+}
+func toTypes(keys []componentKey) []reflect.Type {
+	types := make([]reflect.Type, len(keys))
+	for i, key := range keys {
+		types[i] = key.t
+	}
+	return types
+}
+func formatPath(keys []componentKey) string {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.String()
+	}
+	return strings.Join(names, " -> ")
 }
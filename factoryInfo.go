@@ -1,12 +1,120 @@
 package dijct
 
-import "reflect"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+)
 
 type (
 	factoryInfo struct {
-		target        reflect.Value
-		ins           []reflect.Type
-		isFunc        bool
+		target reflect.Value
+		params []parameter
+		// results はコンストラクタの返り値で、outs は Out を展開して登録するタイプです
+		results    []result
+		outs       []reflect.Type
+		isFunc     bool
+		hasCleanup bool
+		// fields は返り値の構造体のフィールドへの注入です。コンストラクタの引数の後に解決します
+		fields        []fieldInjection
 		lifetimeScope LifetimeScope
+		disposer      func(instance interface{}) error
+		// replaced は Replace で置き換えた登録をキーごとに保持します
+		replaced map[componentKey][]component
+		// mu は ContainerManaged のコンストラクタが並行して呼ばれないようにします
+		mu sync.Mutex
+	}
+	// component は登録されたタイプを生成する factoryInfo と、その返り値の位置です
+	component struct {
+		factoryInfo *factoryInfo
+		index       int
+		// seq は親子のコンテナを通した登録順です
+		seq uint64
+		// owner は登録したコンテナです。ContainerManaged のインスタンスは owner が生成して保持します
+		owner *container
+	}
+	// componentList は同じキーで登録されたコンポーネントです
+	componentList struct {
+		components []component
+		// extends の場合は親コンテナの登録に追加したものとして扱います
+		extends bool
+	}
+	// dependency はコンポーネントの生成に必要な依存先です
+	dependency struct {
+		key componentKey
+		// optional の場合は登録がなければゼロ値を注入します
+		optional bool
+	}
+	// componentKey は登録されたタイプと名前の組です
+	componentKey struct {
+		t    reflect.Type
+		name string
 	}
 )
+
+func (k componentKey) String() string {
+	if k.name == "" {
+		return k.t.String()
+	}
+	return fmt.Sprintf("%v[%s]", k.t, k.name)
+}
+
+// function はコンストラクタの関数を返します。定数と構造体のタイプで登録した場合は nil を返します
+func (f *factoryInfo) function() *runtime.Func {
+	if !f.isFunc {
+		return nil
+	}
+	fn := runtime.FuncForPC(f.target.Pointer())
+	if fn == nil || fn.Name() == "reflect.makeFuncStub" {
+		return nil
+	}
+	return fn
+}
+
+// name はコンストラクタの関数名を返します。定数と構造体のタイプで登録した場合はタイプ名を返します
+func (f *factoryInfo) name() string {
+	if fn := f.function(); fn != nil {
+		return fn.Name()
+	}
+	if !f.isFunc {
+		return f.target.Type().String()
+	}
+	return f.outs[0].String()
+}
+
+// dependencies はコンストラクタの引数とフィールドの依存先を解決する順に返します
+func (f *factoryInfo) dependencies() []dependency {
+	deps := dependenciesOf(f.params)
+	for _, fi := range f.fields {
+		deps = append(deps, fi.dependency)
+	}
+	return deps
+}
+
+// call は dependencies の順に解決した args でコンストラクタを呼び出して、error とクリーンアップ関数を除いた返り値と、破棄が必要なインスタンスを返します。
+// フィールドへの注入に失敗した場合は返り値を返さないため、生成したインスタンスはここで破棄します
+func (f *factoryInfo) call(args []reflect.Value) ([]reflect.Value, []disposable, error) {
+	if !f.isFunc {
+		return []reflect.Value{f.target}, nil, nil
+	}
+	n := len(args) - len(f.fields)
+	values := f.target.Call(buildArgs(f.params, args[:n]))
+	if err := getError(values); err != nil {
+		return nil, nil, err
+	}
+	var cleanup func()
+	if f.hasCleanup {
+		cleanup, _ = values[len(f.results)].Interface().(func())
+	}
+	outs := flattenResults(values[:len(f.results)], f.results)
+	if err := injectFields(outs, f.fields, args[n:]); err != nil {
+		if _, e := disposeAll(context.Background(), newDisposables(f, outs, cleanup)); e != nil {
+			return nil, nil, errors.Join(err, e)
+		}
+		return nil, nil, err
+	}
+	return outs, newDisposables(f, outs, cleanup), nil
+}
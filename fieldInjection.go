@@ -0,0 +1,135 @@
+package dijct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// tagName はコンテナから注入するフィールドに指定するタグです。
+// `dijct:""` でタイプから、`dijct:"name=primary"` で名前付きの登録から注入し、optional を指定すると登録がない場合にゼロ値のままにします
+const tagName = "dijct"
+
+type (
+	// structField はコンテナから解決して設定する構造体のフィールドです
+	structField struct {
+		dependency
+		// index は reflect.Value.Field に指定するフィールドの位置です
+		index    int
+		exported bool
+	}
+	// fieldInjection はコンストラクタの返り値のフィールドへの注入です
+	fieldInjection struct {
+		structField
+		// output は注入先の返り値の位置です
+		output int
+	}
+)
+
+// toConstructor は構造体のタイプをゼロ値を返すコンストラクタに変換します。ポインタのタイプの場合は new したポインタを返します
+func toConstructor(target Target) Target {
+	t, ok := target.(reflect.Type)
+	if !ok || structType(t) == nil {
+		return target
+	}
+	fn := reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{t}, false), func([]reflect.Value) []reflect.Value {
+		if t.Kind() == reflect.Ptr {
+			return []reflect.Value{reflect.New(t.Elem())}
+		}
+		return []reflect.Value{reflect.Zero(t)}
+	})
+	return fn.Interface()
+}
+
+// structType は t が構造体または構造体のポインタであれば構造体のタイプを、そうでなければ nil を返します
+func structType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// getFieldInjections は返り値の構造体のうちタグを指定したフィールドへの注入を返します。
+// 非公開のフィールドは unexported の場合のみ注入します
+func getFieldInjections(outs []reflect.Type, unexported bool) ([]fieldInjection, error) {
+	var injections []fieldInjection
+	for i, out := range outs {
+		st := structType(out)
+		if st == nil {
+			continue
+		}
+		for j := 0; j < st.NumField(); j++ {
+			field := st.Field(j)
+			tag, ok := field.Tag.Lookup(tagName)
+			if !ok {
+				continue
+			}
+			exported := field.PkgPath == ""
+			if !exported && !unexported {
+				return nil, fmt.Errorf("%w。(%v.%s)", ErrUnexportedField, st, field.Name)
+			}
+			dep, err := parseTag(field.Type, tag)
+			if err != nil {
+				return nil, fmt.Errorf("%w。(%v.%s)", err, st, field.Name)
+			}
+			injections = append(injections, fieldInjection{structField: structField{dependency: dep, index: j, exported: exported}, output: i})
+		}
+	}
+	return injections, nil
+}
+
+// parseTag は name=<登録名> と optional をカンマ区切りで解釈します
+func parseTag(t reflect.Type, tag string) (dependency, error) {
+	dep := dependency{key: componentKey{t: t}}
+	if tag == "" {
+		return dep, nil
+	}
+	for _, option := range strings.Split(tag, ",") {
+		switch option = strings.TrimSpace(option); {
+		case option == "optional":
+			dep.optional = true
+		case strings.HasPrefix(option, "name="):
+			dep.key.name = strings.TrimPrefix(option, "name=")
+		default:
+			return dependency{}, fmt.Errorf("%w: %q", ErrInvalidTag, option)
+		}
+	}
+	return dep, nil
+}
+
+// injectFields は返り値のフィールドに values を設定します。構造体の値の場合は複製して設定します。
+// values が無効な値の場合はゼロ値のままにします
+func injectFields(outs []reflect.Value, injections []fieldInjection, values []reflect.Value) error {
+	copied := make(map[int]bool)
+	for i, fi := range injections {
+		out := outs[fi.output]
+		if out.Kind() == reflect.Ptr {
+			if out.IsNil() {
+				return fmt.Errorf("%w。(%v)", ErrNilStruct, out.Type())
+			}
+		} else if !copied[fi.output] {
+			v := reflect.New(out.Type())
+			v.Elem().Set(out)
+			outs[fi.output] = v.Elem()
+			copied[fi.output] = true
+		}
+		if !values[i].IsValid() {
+			continue
+		}
+		fi.set(reflect.Indirect(outs[fi.output]), values[i])
+	}
+	return nil
+}
+
+// set はアドレスを取得できる構造体の v にフィールドの値を設定します
+func (f structField) set(v reflect.Value, value reflect.Value) {
+	field := v.Field(f.index)
+	if !f.exported {
+		field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+	}
+	field.Set(value)
+}
@@ -0,0 +1,120 @@
+package dijct
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Register はコンストラクタまたは定数を T として登録します
+func Register[T any](c Container, target Target, options ...RegisterOptions) error {
+	option, err := withInterface(target, typeOf[T](), options)
+	if err != nil {
+		return err
+	}
+	return c.Register(target, option)
+}
+
+// Replace はコンストラクタまたは定数で T の既存の登録を置き換えます
+func Replace[T any](c Container, target Target, options ...RegisterOptions) error {
+	option, err := withInterface(target, typeOf[T](), options)
+	if err != nil {
+		return err
+	}
+	return c.Replace(target, option)
+}
+
+// RegisterAs は T を返すコンストラクタを I として登録します。
+// コンストラクタが複数の値を返す場合、I は T の返り値に割り当て、T 以外のポインタの返り値は登録しません
+func RegisterAs[I, T any](c Container, target Target, options ...RegisterOptions) error {
+	if len(options) > 1 {
+		return newRegistrationError(reflect.TypeOf(target), ErrNoMultipleOption)
+	}
+	option := RegisterOptions{}
+	if len(options) == 1 {
+		option = options[0]
+	}
+	option.as = &binding{i: typeOf[I](), t: typeOf[T]()}
+	return c.Register(target, option)
+}
+
+// RegisterStruct は構造体 T を dijct タグを指定したフィールドに注入して生成するよう登録します。
+// T が構造体のポインタの場合は Interfaces を指定してください
+func RegisterStruct[T any](c Container, options ...RegisterOptions) error {
+	return c.Register(typeOf[T](), options...)
+}
+
+// Provide は定数を T として登録します。value が関数の場合もコンストラクタとして扱いません。
+// T がインターフェイスで value が nil の場合は nil を T として登録します
+func Provide[T any](c Container, value T, options ...RegisterOptions) error {
+	if len(options) > 1 {
+		return newRegistrationError(typeOf[T](), ErrNoMultipleOption)
+	}
+	option := RegisterOptions{}
+	if len(options) == 1 {
+		option = options[0]
+	}
+	option.constant = typeOf[T]()
+	option.Interfaces = append(append([]reflect.Type{}, option.Interfaces...), typeOf[T]())
+	return c.Register(value, option)
+}
+
+// Resolve はコンテナから T を解決します
+func Resolve[T any](l ServiceLocator) (T, error) {
+	return ResolveNamed[T](l, "")
+}
+
+// ResolveNamed はコンテナから名前付きで登録された T を解決します
+func ResolveNamed[T any](l ServiceLocator, name string) (T, error) {
+	var v T
+	i, err := l.ResolveNamed(typeOf[T](), name)
+	if err != nil {
+		return v, err
+	}
+	v, _ = i.(T)
+	return v, nil
+}
+
+// MustResolve はコンテナから T を解決します。解決できない場合は panic します
+func MustResolve[T any](l ServiceLocator) T {
+	v, err := Resolve[T](l)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// withInterface は t を Interfaces に追加したオプションを返します
+func withInterface(target Target, t reflect.Type, options []RegisterOptions) (RegisterOptions, error) {
+	if len(options) > 1 {
+		return RegisterOptions{}, newRegistrationError(reflect.TypeOf(target), ErrNoMultipleOption)
+	}
+	if target == nil {
+		return RegisterOptions{}, newRegistrationError(t, ErrRequireFunction)
+	}
+	outs, _, err := getTargetReflectionInfos(target)
+	if err != nil {
+		return RegisterOptions{}, newRegistrationError(reflect.TypeOf(target), err)
+	}
+	if indexOfAssignable(outs, t) < 0 {
+		return RegisterOptions{}, newRegistrationError(outs[0], fmt.Errorf("%w。(%v)", ErrNotAssignable, t))
+	}
+	option := RegisterOptions{}
+	if len(options) == 1 {
+		option = options[0]
+	}
+	option.Interfaces = append(append([]reflect.Type{}, option.Interfaces...), t)
+	return option, nil
+}
+
+func indexOf(types []reflect.Type, t reflect.Type) int {
+	for i, v := range types {
+		if v == t {
+			return i
+		}
+	}
+	return -1
+}
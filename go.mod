@@ -2,4 +2,4 @@ module github.com/wakuwaku3/dijct
 
 require github.com/google/uuid v1.2.0
 
-go 1.16
+go 1.20
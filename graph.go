@@ -0,0 +1,129 @@
+package dijct
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type (
+	// Graph はコンテナに登録されたコンポーネントの依存関係です
+	Graph struct {
+		Nodes []GraphNode `json:"nodes"`
+		Edges []GraphEdge `json:"edges"`
+	}
+	// GraphNode は登録されたコンポーネントです
+	GraphNode struct {
+		// ID はグラフ内で一意な識別子です。同じタイプと登録名で複数登録した場合は登録順の番号が付きます
+		ID            string        `json:"id"`
+		Type          string        `json:"type"`
+		Name          string        `json:"name,omitempty"`
+		LifetimeScope LifetimeScope `json:"lifetimeScope"`
+		// Constructor はコンストラクタで登録した場合に true、定数で登録した場合に false です
+		Constructor bool `json:"constructor"`
+		// Inherited は親コンテナで登録したコンポーネントの場合に true です
+		Inherited bool `json:"inherited"`
+		// Replaces は Replace で置き換えた登録のコンストラクタ名です
+		Replaces []string `json:"replaces,omitempty"`
+	}
+	// GraphEdge は From のコンポーネントが To のコンポーネントに依存していることを表します
+	GraphEdge struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+)
+
+// Graph は factoryInfo の依存先を辿って依存関係のグラフを生成します。
+// 差分を比較できるよう、ノードとエッジは ID の順に並べます。コンテナのオプションが正しくない場合は空のグラフを返します
+func (c *container) Graph() *Graph {
+	if c.err != nil {
+		return &Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	}
+	elems := c.getAllComponents()
+	ids := make(map[keyedComponent]string, len(elems))
+	counts := make(map[componentKey]int)
+	for _, elem := range elems {
+		counts[elem.key]++
+	}
+	indexes := make(map[componentKey]int)
+	g := &Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	for _, elem := range elems {
+		id := elem.key.String()
+		if counts[elem.key] > 1 {
+			id = fmt.Sprintf("%s#%d", id, indexes[elem.key])
+			indexes[elem.key]++
+		}
+		ids[elem] = id
+		g.Nodes = append(g.Nodes, GraphNode{
+			ID:            id,
+			Type:          elem.key.t.String(),
+			Name:          elem.key.name,
+			LifetimeScope: elem.component.factoryInfo.lifetimeScope,
+			Constructor:   elem.component.factoryInfo.isFunc,
+			Inherited:     elem.component.owner != c,
+			Replaces:      replacedNames(elem.component.factoryInfo.replaced[elem.key]),
+		})
+	}
+	edges := make(map[GraphEdge]struct{})
+	for _, elem := range elems {
+		for _, d := range elem.component.factoryInfo.dependencies() {
+			if c.isBuiltin(d.key, nil) {
+				continue
+			}
+			for _, dep := range c.getDependencies(d.key) {
+				edges[GraphEdge{From: ids[elem], To: ids[dep]}] = struct{}{}
+			}
+		}
+	}
+	for edge := range edges {
+		g.Edges = append(g.Edges, edge)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g
+}
+
+func replacedNames(cmps []component) []string {
+	var names []string
+	for _, cmp := range cmps {
+		names = append(names, cmp.factoryInfo.name())
+	}
+	return names
+}
+
+// DOT は Graphviz の DOT 形式で出力します。
+// 定数は四角形、親コンテナで登録したコンポーネントは破線で表します
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph dijct {\n")
+	for _, node := range g.Nodes {
+		shape := "ellipse"
+		if !node.Constructor {
+			shape = "box"
+		}
+		style := "solid"
+		if node.Inherited {
+			style = "dashed"
+		}
+		label := fmt.Sprintf("%s\n%v", node.ID, node.LifetimeScope)
+		fmt.Fprintf(&b, "\t%q [label=%q, shape=%s, style=%s];\n", node.ID, label, shape, style)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "\t%q -> %q;\n", edge.From, edge.To)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// JSON はインデントした JSON 形式で出力します
+func (g *Graph) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
@@ -0,0 +1,66 @@
+package dijct
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// EventKind はコンテナで発生したイベントの種類です
+type EventKind int
+
+const (
+	// EventRegistered は Register で登録した場合のイベントです
+	EventRegistered EventKind = iota
+	// EventReplaced は Replace で登録を置き換えた場合のイベントです
+	EventReplaced
+	// EventUnregistered は Unregister で登録を削除した場合のイベントです
+	EventUnregistered
+	// EventConstructed はコンストラクタを呼び出した場合のイベントです。コンストラクタが失敗した場合は Err を設定します
+	EventConstructed
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventRegistered:
+		return "Registered"
+	case EventReplaced:
+		return "Replaced"
+	case EventUnregistered:
+		return "Unregistered"
+	case EventConstructed:
+		return "Constructed"
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+type (
+	// Event は ContainerOptions の Hook に通知するイベントです
+	Event struct {
+		Kind          EventKind
+		Type          reflect.Type
+		Name          string
+		LifetimeScope LifetimeScope
+		// Constructor はコンストラクタの関数名です
+		Constructor string
+		// Duration は EventConstructed の場合のコンストラクタの呼び出しにかかった時間です
+		Duration time.Duration
+		Err      error
+	}
+)
+
+// emit は Hook が指定されていればイベントを通知します。ロックを取得したまま呼び出さないでください。
+// 解決中のイベントは resolveContext の emit で通知します
+func (c *container) emit(events ...Event) {
+	if c.options.Hook == nil {
+		return
+	}
+	for _, e := range events {
+		c.options.Hook(e)
+	}
+}
+
+// newEvent は key で登録された f のイベントを生成します
+func newEvent(kind EventKind, key componentKey, f *factoryInfo) Event {
+	return Event{Kind: kind, Type: key.t, Name: key.name, LifetimeScope: f.lifetimeScope, Constructor: f.name()}
+}
@@ -0,0 +1,106 @@
+package dijct
+
+import (
+	"reflect"
+	"sync"
+)
+
+type (
+	// Lazy を依存先に指定すると、T の解決を最初に Get を呼び出すまで遅延します。
+	// 解決は Lazy を注入した Invoke と InvokeManaged のインスタンスを共有して T のライフタイムに従って行うため、InvokeManaged の T は同じ Invoke 内のインスタンスになります。
+	// 複数の goroutine から並行に Get を呼び出すことができます。
+	// Invoke の終了後に初めて Get を呼び出した場合、その解決で生成した InvokeManaged のインスタンスは破棄されません
+	Lazy[T any] struct {
+		get func() (T, error)
+	}
+	// deferred は Lazy[T] を型引数によらずに判別するためのインターフェイスです
+	deferred interface {
+		deferredElem() reflect.Type
+		newDeferred(resolve func() (reflect.Value, error)) reflect.Value
+	}
+)
+
+var deferredInterfaceType = reflect.TypeOf((*deferred)(nil)).Elem()
+
+// Get は最初の呼び出しで T を解決し、以降は同じ結果を返します
+func (l Lazy[T]) Get() (T, error) {
+	if l.get == nil {
+		var v T
+		return v, ErrNotRegisteredComponent
+	}
+	return l.get()
+}
+func (Lazy[T]) deferredElem() reflect.Type {
+	return typeOf[T]()
+}
+func (Lazy[T]) newDeferred(resolve func() (reflect.Value, error)) reflect.Value {
+	var once sync.Once
+	var v T
+	var err error
+	return reflect.ValueOf(Lazy[T]{get: func() (T, error) {
+		once.Do(func() {
+			var rv reflect.Value
+			if rv, err = resolve(); err == nil {
+				v, _ = rv.Interface().(T)
+			}
+		})
+		return v, err
+	}})
+}
+
+// unwrapDeferred は key が Lazy[T] または func() (T, error) の場合に同じ登録名の T を返します
+func unwrapDeferred(key componentKey) (componentKey, bool) {
+	t := key.t
+	switch {
+	case t.Kind() == reflect.Struct && t.Implements(deferredInterfaceType):
+		elem := reflect.Zero(t).Interface().(deferred).deferredElem()
+		return componentKey{t: elem, name: key.name}, true
+	case t.Kind() == reflect.Func && t.NumIn() == 0 && t.NumOut() == 2 && t.Out(1) == errorType:
+		return componentKey{t: t.Out(0), name: key.name}, true
+	}
+	return key, false
+}
+
+// resolveDeferred は elem を最初の Get で解決する Lazy[T]、または呼び出す度に elem のライフタイムに従って解決する func() (T, error) を返します。
+// elem が登録されていない場合は呼び出しを待たずにエラーを返します
+func (c *container) resolveDeferred(key componentKey, elem componentKey, rc *resolveContext) (*reflect.Value, error) {
+	if !c.isResolvable(elem, rc.scope) {
+		return nil, newResolveError(elem, rc.path, ErrNotRegisteredComponent)
+	}
+	resolve := func() (reflect.Value, error) {
+		v, err := c.resolve(elem, rc.deferred())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		typed := reflect.New(elem.t).Elem()
+		typed.Set(*v)
+		return typed, nil
+	}
+	if key.t.Kind() == reflect.Struct {
+		v := reflect.Zero(key.t).Interface().(deferred).newDeferred(resolve)
+		return &v, nil
+	}
+	fn := reflect.MakeFunc(key.t, func([]reflect.Value) []reflect.Value {
+		v, err := resolve()
+		if err != nil {
+			return []reflect.Value{reflect.Zero(elem.t), reflect.ValueOf(&err).Elem()}
+		}
+		return []reflect.Value{v, reflect.Zero(errorType)}
+	})
+	return &fn, nil
+}
+
+// unwrap は Optional[T]、Lazy[T] または func() (T, error) の T を返します
+func unwrap(key componentKey) componentKey {
+	for {
+		if elem, ok := unwrapOptional(key); ok {
+			key = elem
+			continue
+		}
+		if elem, ok := unwrapDeferred(key); ok {
+			key = elem
+			continue
+		}
+		return key
+	}
+}
@@ -1,11 +1,58 @@
 package dijct
 
-// LifetimeScope はインスタンスのライフタイムスコープです
+import "fmt"
+
+// LifetimeScope はインスタンスのライフタイムスコープです。
+// ゼロ値はコンテナの既定のライフタイムを表すため、ContainerManaged は 0 ではありません。
+// RegisterOptions で LifetimeScope を指定しない場合は ContainerManaged ではなくコンテナの既定のライフタイムで登録します
 type LifetimeScope int
 
 const (
+	// unspecifiedLifetimeScope は RegisterOptions で LifetimeScope を指定しなかった場合のゼロ値です。コンテナの既定のライフタイムで登録します
+	unspecifiedLifetimeScope LifetimeScope = iota
 	// ContainerManaged の場合、そのコンテナ及び派生したコンテナでインスタンスは一意です
-	ContainerManaged LifetimeScope = iota
+	ContainerManaged
 	// InvokeManaged の場合、その呼び出し内でインスタンスは一意です
 	InvokeManaged
+	// ScopeManaged の場合、BeginScope で開始したスコープ内でインスタンスは一意です。
+	// スコープ外から解決した場合は InvokeManaged と同様に扱います
+	ScopeManaged
+	// Transient の場合、依存先として解決される度に新しいインスタンスを生成します
+	Transient
 )
+
+func (l LifetimeScope) String() string {
+	switch l {
+	case ContainerManaged:
+		return "ContainerManaged"
+	case InvokeManaged:
+		return "InvokeManaged"
+	case ScopeManaged:
+		return "ScopeManaged"
+	case Transient:
+		return "Transient"
+	}
+	return fmt.Sprintf("LifetimeScope(%d)", int(l))
+}
+
+// valid は l が定義されたライフタイムかを返します
+func (l LifetimeScope) valid() bool {
+	return l >= ContainerManaged && l <= Transient
+}
+
+// rank はインスタンスが保持される長さの順位です。
+// Transient のインスタンスは InvokeManaged と同様に呼び出しの終了時に破棄されるため同じ順位になります
+func (l LifetimeScope) rank() int {
+	switch l {
+	case ContainerManaged:
+		return 2
+	case ScopeManaged:
+		return 1
+	}
+	return 0
+}
+
+// MarshalText は String と同じ名前で出力します
+func (l LifetimeScope) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
@@ -0,0 +1,56 @@
+package dijct
+
+import "reflect"
+
+type (
+	// Optional を依存先に指定すると、T が登録されていない場合も解決を失敗させずに Present が false のゼロ値を注入します。
+	// 登録されている場合は Value に T を設定し、Present を true にします
+	Optional[T any] struct {
+		Value   T
+		Present bool
+	}
+	// optional は Optional[T] を型引数によらずに判別するためのインターフェイスです
+	optional interface {
+		optionalElem() reflect.Type
+	}
+)
+
+var optionalInterfaceType = reflect.TypeOf((*optional)(nil)).Elem()
+
+func (Optional[T]) optionalElem() reflect.Type {
+	return typeOf[T]()
+}
+
+// unwrapOptional は key が Optional[T] の場合に同じ登録名の T を返します
+func unwrapOptional(key componentKey) (componentKey, bool) {
+	if key.t.Kind() != reflect.Struct || !key.t.Implements(optionalInterfaceType) {
+		return key, false
+	}
+	elem := reflect.Zero(key.t).Interface().(optional).optionalElem()
+	return componentKey{t: elem, name: key.name}, true
+}
+
+// resolveOptional は elem が登録されていれば解決した値を、登録されていなければ Present が false の Optional[T] を返します。
+// elem の依存先の解決に失敗した場合はエラーを返します
+func (c *container) resolveOptional(key componentKey, elem componentKey, rc *resolveContext) (*reflect.Value, error) {
+	if !c.isResolvable(elem, rc.scope) {
+		v := newOptional(key.t, nil)
+		return &v, nil
+	}
+	v, err := c.resolve(elem, rc)
+	if err != nil {
+		return nil, err
+	}
+	o := newOptional(key.t, v)
+	return &o, nil
+}
+
+// newOptional は v を設定した Optional[T] を生成します。v が nil の場合は Present が false になります
+func newOptional(t reflect.Type, v *reflect.Value) reflect.Value {
+	o := reflect.New(t).Elem()
+	if v != nil {
+		o.FieldByName("Value").Set(*v)
+		o.FieldByName("Present").SetBool(true)
+	}
+	return o
+}
@@ -0,0 +1,167 @@
+package dijct
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type (
+	// In を埋め込んだ構造体をコンストラクタまたは Invoke の引数にすると、公開されたフィールドごとにコンテナから解決します。
+	// フィールドには dijct タグで name と optional を指定できます
+	In struct{}
+	// Out を埋め込んだ構造体をコンストラクタの返り値にすると、公開されたフィールドごとにコンポーネントとして登録します。
+	// フィールドには dijct タグで name を指定できます
+	Out struct{}
+
+	// parameter はコンストラクタまたは Invoke の引数です
+	parameter struct {
+		t reflect.Type
+		// fields は In を埋め込んだ構造体の場合のフィールドです。それ以外の場合は nil です
+		fields []structField
+	}
+	// result はコンストラクタの返り値です
+	result struct {
+		t reflect.Type
+		// fields は Out を埋め込んだ構造体の場合のフィールドの位置です。それ以外の場合は nil です
+		fields []int
+	}
+)
+
+var (
+	inType  = reflect.TypeOf(In{})
+	outType = reflect.TypeOf(Out{})
+)
+
+// embeds は t が marker を埋め込んだ構造体かを返します
+func embeds(t reflect.Type, marker reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// getParameters は In を埋め込んだ構造体の引数をフィールドごとの依存先に展開します
+func getParameters(ins []reflect.Type) ([]parameter, error) {
+	params := make([]parameter, len(ins))
+	for i, in := range ins {
+		params[i] = parameter{t: in}
+		if !embeds(in, inType) {
+			continue
+		}
+		params[i].fields = []structField{}
+		for j := 0; j < in.NumField(); j++ {
+			field := in.Field(j)
+			if field.Anonymous && field.Type == inType {
+				continue
+			}
+			if field.PkgPath != "" {
+				return nil, fmt.Errorf("%w。(%v.%s)", ErrUnexportedParameterField, in, field.Name)
+			}
+			dep, err := parseTag(field.Type, field.Tag.Get(tagName))
+			if err != nil {
+				return nil, fmt.Errorf("%w。(%v.%s)", err, in, field.Name)
+			}
+			params[i].fields = append(params[i].fields, structField{dependency: dep, index: j, exported: true})
+		}
+	}
+	return params, nil
+}
+
+// dependenciesOf は引数の依存先を解決する順に返します
+func dependenciesOf(params []parameter) []dependency {
+	var deps []dependency
+	for _, p := range params {
+		if p.fields == nil {
+			deps = append(deps, dependency{key: componentKey{t: p.t}})
+			continue
+		}
+		for _, f := range p.fields {
+			deps = append(deps, f.dependency)
+		}
+	}
+	return deps
+}
+
+// buildArgs は dependenciesOf の順に解決した values から引数を組み立てます。無効な値はゼロ値にします
+func buildArgs(params []parameter, values []reflect.Value) []reflect.Value {
+	args := make([]reflect.Value, len(params))
+	n := 0
+	for i, p := range params {
+		if p.fields == nil {
+			args[i] = values[n]
+			if !args[i].IsValid() {
+				args[i] = reflect.Zero(p.t)
+			}
+			n++
+			continue
+		}
+		v := reflect.New(p.t).Elem()
+		for _, f := range p.fields {
+			if values[n].IsValid() {
+				f.set(v, values[n])
+			}
+			n++
+		}
+		args[i] = v
+	}
+	return args
+}
+
+// getResults は Out を埋め込んだ構造体の返り値をフィールドごとに展開し、登録するタイプとフィールドに指定した登録名を返します
+func getResults(outs []reflect.Type) ([]reflect.Type, []string, []result, error) {
+	var types []reflect.Type
+	var names []string
+	results := make([]result, len(outs))
+	for i, out := range outs {
+		results[i] = result{t: out}
+		if !embeds(out, outType) {
+			types = append(types, out)
+			names = append(names, "")
+			continue
+		}
+		results[i].fields = []int{}
+		for j := 0; j < out.NumField(); j++ {
+			field := out.Field(j)
+			if field.Anonymous && field.Type == outType {
+				continue
+			}
+			if field.PkgPath != "" {
+				return nil, nil, nil, fmt.Errorf("%w。(%v.%s)", ErrUnexportedParameterField, out, field.Name)
+			}
+			dep, err := parseTag(field.Type, field.Tag.Get(tagName))
+			if err == nil && dep.optional {
+				err = fmt.Errorf("%w: %q", ErrInvalidTag, "optional")
+			}
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("%w。(%v.%s)", err, out, field.Name)
+			}
+			types = append(types, field.Type)
+			names = append(names, dep.key.name)
+			results[i].fields = append(results[i].fields, j)
+		}
+	}
+	if len(types) == 0 {
+		return nil, nil, nil, ErrRequireResponse
+	}
+	return types, names, results, nil
+}
+
+// flattenResults は Out を埋め込んだ構造体の返り値をフィールドの値に展開します
+func flattenResults(values []reflect.Value, results []result) []reflect.Value {
+	var outs []reflect.Value
+	for i, r := range results {
+		if r.fields == nil {
+			outs = append(outs, values[i])
+			continue
+		}
+		for _, j := range r.fields {
+			outs = append(outs, values[i].Field(j))
+		}
+	}
+	return outs
+}
@@ -1,9 +1,16 @@
 package dijct
 
 import (
+	"context"
 	"reflect"
 )
 
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	cleanupType = reflect.TypeOf((func())(nil))
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
 func getIns(t reflect.Type) []reflect.Type {
 	len := t.NumIn()
 	in := make([]reflect.Type, len)
@@ -12,22 +19,48 @@ func getIns(t reflect.Type) []reflect.Type {
 	}
 	return in
 }
-func getOut(t reflect.Type) (reflect.Type, error) {
+
+// getOuts は末尾の error とクリーンアップ関数を除いた返り値のタイプを返します
+func getOuts(t reflect.Type) ([]reflect.Type, error) {
 	l := t.NumOut()
+	if l > 0 && t.Out(l-1) == errorType {
+		l--
+	}
+	if l > 1 && t.Out(l-1) == cleanupType {
+		l--
+	}
 	if l < 1 {
 		return nil, ErrRequireResponse
 	}
-	return t.Out(0), nil
+	outs := make([]reflect.Type, l)
+	for i := 0; i < l; i++ {
+		outs[i] = t.Out(i)
+	}
+	return outs, nil
 }
-func getTargetReflectionInfos(target Target) (out reflect.Type, in []reflect.Type, err error) {
-	t := reflect.TypeOf(target)
+
+// hasCleanup は コンストラクタが func(...) (T, func(), error) のようにクリーンアップ関数を返すかを判定します
+func hasCleanup(t reflect.Type, outs []reflect.Type) bool {
+	return t.Kind() == reflect.Func && t.NumOut() > len(outs) && t.Out(len(outs)) == cleanupType
+}
+func getTargetReflectionInfos(target Target) (outs []reflect.Type, in []reflect.Type, err error) {
+	t := reflect.TypeOf(toConstructor(target))
 	if t.Kind() == reflect.Func {
-		out, err := getOut(t)
+		outs, err := getOuts(t)
 		if err != nil {
 			return nil, nil, err
 		}
 		ins := getIns(t)
-		return out, ins, nil
+		return outs, ins, nil
+	}
+	return []reflect.Type{t}, nil, nil
+}
+
+// getError は末尾の返り値が nil でない error の場合に返します
+func getError(outs []reflect.Value) error {
+	l := len(outs)
+	if l > 0 && outs[l-1].Type() == errorType && !outs[l-1].IsNil() {
+		return outs[l-1].Interface().(error)
 	}
-	return t, nil, nil
+	return nil
 }
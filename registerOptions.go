@@ -7,7 +7,26 @@ import (
 type (
 	// RegisterOptions は 登録時のオプションです
 	RegisterOptions struct {
+		// LifetimeScope を指定しない場合はコンテナの既定のライフタイムで登録します
 		LifetimeScope LifetimeScope
 		Interfaces    []reflect.Type
+		// Name を指定すると、同じタイプの他の登録と区別して名前付きで登録します
+		Name string
+		// Multiple を指定すると、同じタイプの登録を上書きせずに追加します。
+		// 追加された登録は []T または map[string]T の引数でまとめて解決できます
+		Multiple bool
+		// Disposer を指定すると、インスタンスの破棄時に io.Closer の代わりに呼び出します
+		Disposer func(instance interface{}) error
+		// UnexportedFields を指定すると、タグを指定した非公開のフィールドにも注入します
+		UnexportedFields bool
+		// as は RegisterAs で指定した登録です
+		as *binding
+		// constant は Provide で登録する場合の T です。関数もコンストラクタとして扱わずに定数として登録し、nil の場合は T のゼロ値を登録します
+		constant reflect.Type
+	}
+	// binding は返り値のタイプ t を i として登録することを表します
+	binding struct {
+		i reflect.Type
+		t reflect.Type
 	}
 )
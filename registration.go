@@ -0,0 +1,127 @@
+package dijct
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+type (
+	// Registration は登録されたコンポーネントの情報です
+	Registration struct {
+		Type          reflect.Type
+		Name          string
+		LifetimeScope LifetimeScope
+		// Constructor はコンストラクタの関数名です。定数と構造体のタイプで登録した場合はタイプ名です
+		Constructor string
+		// File と Line はコンストラクタの定義位置です。定数と構造体のタイプで登録した場合は空です
+		File string
+		Line int
+		// Dependencies はコンストラクタの引数と注入するフィールドのタイプです
+		Dependencies []reflect.Type
+		// Cached は ContainerManaged のインスタンスが生成済みの場合に true です
+		Cached bool
+		// Inherited は親コンテナで登録したコンポーネントの場合に true です
+		Inherited bool
+		// Replaces は Replace で置き換えた登録のコンストラクタ名です
+		Replaces []string
+	}
+)
+
+// Has は名前を指定せずに登録された t が親コンテナも含めて登録されているかを返します。組み込みのタイプは含みません
+func (c *container) Has(t reflect.Type) bool {
+	return c.HasNamed(t, "")
+}
+
+// HasNamed は名前付きで登録された t が親コンテナも含めて登録されているかを返します。コンテナのオプションが正しくない場合は false を返します
+func (c *container) HasNamed(t reflect.Type, name string) bool {
+	if c.err != nil {
+		return false
+	}
+	return len(c.getComponentsByKey(componentKey{t: t, name: name})) > 0
+}
+
+// Unregister は名前を指定せずに登録された t の登録をこのコンテナから削除します。名前付きの登録は UnregisterNamed で削除します
+func (c *container) Unregister(t reflect.Type) {
+	c.UnregisterNamed(t, "")
+}
+
+// UnregisterNamed は名前付きで登録された t の登録をこのコンテナから削除し、他のタイプで登録されていないインスタンスのキャッシュを破棄します。
+// 親コンテナの登録は削除しません。生成済みのインスタンスは Close で破棄します
+func (c *container) UnregisterNamed(t reflect.Type, name string) {
+	if c.err != nil {
+		return
+	}
+	key := componentKey{t: t, name: name}
+	c.mu.Lock()
+	old, ok := c.components[key]
+	delete(c.components, key)
+	for _, cmp := range old.components {
+		if !c.isRegistered(cmp.factoryInfo) {
+			delete(c.cache, cmp.factoryInfo)
+		}
+	}
+	c.mu.Unlock()
+	if ok {
+		c.decorations.reset()
+	}
+	for _, cmp := range old.components {
+		c.emit(newEvent(EventUnregistered, key, cmp.factoryInfo))
+	}
+}
+
+// Registrations は親コンテナも含めて登録された全てのコンポーネントを登録順に返します。コンテナのオプションが正しくない場合は nil を返します
+func (c *container) Registrations() []Registration {
+	if c.err != nil {
+		return nil
+	}
+	elems := c.getAllComponents()
+	sort.SliceStable(elems, func(i, j int) bool {
+		a, b := elems[i].component, elems[j].component
+		if a.seq != b.seq {
+			return a.seq < b.seq
+		}
+		if a.index != b.index {
+			return a.index < b.index
+		}
+		return elems[i].key.String() < elems[j].key.String()
+	})
+	registrations := make([]Registration, len(elems))
+	for i, elem := range elems {
+		f := elem.component.factoryInfo
+		r := Registration{
+			Type:          elem.key.t,
+			Name:          elem.key.name,
+			LifetimeScope: f.lifetimeScope,
+			Constructor:   f.name(),
+			Dependencies:  []reflect.Type{},
+			Inherited:     elem.component.owner != c,
+			Replaces:      replacedNames(f.replaced[elem.key]),
+		}
+		if fn := f.function(); fn != nil {
+			r.File, r.Line = fn.FileLine(fn.Entry())
+		}
+		for _, d := range f.dependencies() {
+			r.Dependencies = append(r.Dependencies, d.key.t)
+		}
+		_, r.Cached = elem.component.owner.getCache(f)
+		registrations[i] = r
+	}
+	return registrations
+}
+
+// String は起動時のログなどに出力する1行の表現です
+func (r Registration) String() string {
+	key := componentKey{t: r.Type, name: r.Name}
+	s := fmt.Sprintf("%v(%v) <- %s", key, r.LifetimeScope, r.Constructor)
+	if r.File != "" {
+		s += fmt.Sprintf(" (%s:%d)", r.File, r.Line)
+	}
+	if len(r.Dependencies) > 0 {
+		s += fmt.Sprintf(" %v", r.Dependencies)
+	}
+	if r.Cached {
+		s += " [cached]"
+	}
+	return s
+}
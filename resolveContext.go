@@ -0,0 +1,235 @@
+package dijct
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+type (
+	// resolveContext は 1回の解決処理の状態です。1つの goroutine から使用します
+	resolveContext struct {
+		ctx context.Context
+		// scope は BeginScope で開始したスコープから解決する場合のスコープです
+		scope *scope
+		// invocation は Invoke で生成した InvokeManaged のインスタンスです。Lazy の解決と共有します
+		invocation *invocation
+		// parent は Lazy を注入した解決処理です
+		parent *resolveContext
+		// mu は path と factoryInfos の変更と、Lazy の解決の開始時の複製を排他します
+		mu   sync.Mutex
+		path []componentKey
+		// factoryInfos は path の各要素を生成している factoryInfo です
+		factoryInfos []*factoryInfo
+		// held は保持している生成中のインスタンスのロックの数です
+		held int
+		// events はロックを保持している間に発生したイベントです。全てのロックを解放した後に通知します
+		events []pendingEvent
+	}
+	// pendingEvent は通知を待っているイベントと、通知するコンテナです
+	pendingEvent struct {
+		container *container
+		event     Event
+	}
+	// invocation は 1回の Invoke の間で生成したインスタンスです。Lazy を別の goroutine から解決する場合も共有します
+	invocation struct {
+		mu         sync.Mutex
+		cache      map[*factoryInfo][]reflect.Value
+		buildLocks map[*factoryInfo]*sync.Mutex
+		// disposables は Invoke の終了時に破棄するインスタンスです
+		disposables []disposable
+		// decorations は InvokeManaged のインスタンスに適用したデコレーターの結果です
+		decorations decorations
+		// closed は Invoke が終了した場合に true です。以降に生成したインスタンスはキャッシュせず、破棄もしません
+		closed bool
+	}
+	// lockOwner は生成中のインスタンスのロックを保持している解決処理です
+	lockOwner struct {
+		rc *resolveContext
+		// depth はロックを取得した時点の path の長さです
+		depth int
+	}
+)
+
+// lockTable はインスタンスの生成中に保持するロックの所有者と、ロックを待っている解決処理です。
+// 解決処理をまたいだ待機の循環を検出するため、全てのコンテナで共有します
+var lockTable = struct {
+	mu      sync.Mutex
+	owners  map[*sync.Mutex]lockOwner
+	waiting map[*resolveContext]*sync.Mutex
+}{
+	owners:  make(map[*sync.Mutex]lockOwner),
+	waiting: make(map[*resolveContext]*sync.Mutex),
+}
+
+func newResolveContext(ctx context.Context, s *scope) *resolveContext {
+	return &resolveContext{ctx: ctx, scope: s, invocation: &invocation{
+		cache:      make(map[*factoryInfo][]reflect.Value),
+		buildLocks: make(map[*factoryInfo]*sync.Mutex),
+	}}
+}
+
+// deferred は Lazy の解決に使用する解決処理を生成します。
+// path は呼び出し時点の rc の path を引き継ぐため、生成中のコンポーネントを Lazy から解決すると循環として検出します
+func (rc *resolveContext) deferred() *resolveContext {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return &resolveContext{
+		ctx:          rc.ctx,
+		scope:        rc.scope,
+		invocation:   rc.invocation,
+		parent:       rc,
+		path:         append([]componentKey{}, rc.path...),
+		factoryInfos: append([]*factoryInfo{}, rc.factoryInfos...),
+	}
+}
+
+// actsFor は rc が a 自身か、a の解決処理の中で呼び出した Lazy の解決処理かを返します
+func (rc *resolveContext) actsFor(a *resolveContext) bool {
+	for p := rc; p != nil; p = p.parent {
+		if p == a {
+			return true
+		}
+	}
+	return false
+}
+
+// resolving は f が生成中であれば path 上の位置を、そうでなければ -1 を返します
+func (rc *resolveContext) resolving(f *factoryInfo) int {
+	for i, p := range rc.factoryInfos {
+		if p == f {
+			return i
+		}
+	}
+	return -1
+}
+func (rc *resolveContext) push(key componentKey, f *factoryInfo) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.path = append(rc.path, key)
+	rc.factoryInfos = append(rc.factoryInfos, f)
+}
+func (rc *resolveContext) pop() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.path = rc.path[:len(rc.path)-1]
+	rc.factoryInfos = rc.factoryInfos[:len(rc.factoryInfos)-1]
+}
+
+// lock は生成中のインスタンスのロック l を取得します。
+// l の取得を待つと他の解決処理との間で待機が循環する場合は、取得せずに CircularDependencyError を返します
+func (rc *resolveContext) lock(l *sync.Mutex) error {
+	lockTable.mu.Lock()
+	if cycle := rc.waitCycle(l, make(map[*sync.Mutex]bool)); cycle != nil {
+		lockTable.mu.Unlock()
+		keys := append(rc.path[:len(rc.path):len(rc.path)], cycle...)
+		return newCircularDependencyError(keys[:len(keys)-1], keys[len(keys)-1])
+	}
+	lockTable.waiting[rc] = l
+	lockTable.mu.Unlock()
+	l.Lock()
+	lockTable.mu.Lock()
+	delete(lockTable.waiting, rc)
+	lockTable.owners[l] = lockOwner{rc: rc, depth: len(rc.path)}
+	lockTable.mu.Unlock()
+	rc.held++
+	return nil
+}
+
+// unlock は l を解放し、全てのロックを解放した場合は保持している間に発生したイベントを通知します
+func (rc *resolveContext) unlock(l *sync.Mutex) {
+	lockTable.mu.Lock()
+	delete(lockTable.owners, l)
+	lockTable.mu.Unlock()
+	l.Unlock()
+	rc.held--
+	rc.flush()
+}
+
+// emit は c のイベントを通知します。ロックを保持している場合は全てのロックを解放するまで通知を遅らせます。
+// Hook の中で生成中のコンポーネントを解決しても、解放されないロックを待たないようにするためです
+func (rc *resolveContext) emit(c *container, e Event) {
+	rc.events = append(rc.events, pendingEvent{container: c, event: e})
+	rc.flush()
+}
+func (rc *resolveContext) flush() {
+	if rc.held > 0 {
+		return
+	}
+	events := rc.events
+	rc.events = nil
+	for _, p := range events {
+		p.container.emit(p.event)
+	}
+}
+
+// waitCycle は rc が l を待つ場合に、l の所有者から rc に戻るまでに待機している解決処理の path を返します。
+// 循環しない場合は nil を返します。lockTable をロックして呼び出してください
+func (rc *resolveContext) waitCycle(l *sync.Mutex, visited map[*sync.Mutex]bool) []componentKey {
+	owner, ok := lockTable.owners[l]
+	if !ok {
+		return nil
+	}
+	if rc.actsFor(owner.rc) {
+		return []componentKey{}
+	}
+	for w, wl := range lockTable.waiting {
+		if !w.actsFor(owner.rc) || visited[wl] {
+			continue
+		}
+		visited[wl] = true
+		if rest := rc.waitCycle(wl, visited); rest != nil {
+			var keys []componentKey
+			if owner.depth < len(w.path) {
+				keys = append(keys, w.path[owner.depth:]...)
+			}
+			return append(keys, rest...)
+		}
+	}
+	return nil
+}
+
+func (i *invocation) getCache(f *factoryInfo) ([]reflect.Value, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	v, ok := i.cache[f]
+	return v, ok
+}
+func (i *invocation) setCache(f *factoryInfo, v []reflect.Value, ds []disposable) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.closed {
+		return
+	}
+	i.cache[f] = v
+	i.disposables = append(i.disposables, ds...)
+}
+func (i *invocation) addDisposables(ds []disposable) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.closed {
+		i.disposables = append(i.disposables, ds...)
+	}
+}
+func (i *invocation) getBuildLock(f *factoryInfo) *sync.Mutex {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	l, ok := i.buildLocks[f]
+	if !ok {
+		l = &sync.Mutex{}
+		i.buildLocks[f] = l
+	}
+	return l
+}
+
+// close は Invoke の終了時に呼び出し、破棄するインスタンスを返します
+func (i *invocation) close() []disposable {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	ds := i.disposables
+	i.closed = true
+	i.disposables = nil
+	i.cache = make(map[*factoryInfo][]reflect.Value)
+	i.decorations.reset()
+	return ds
+}
@@ -0,0 +1,123 @@
+package dijct
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+type (
+	scope struct {
+		container   *container
+		mu          sync.Mutex
+		cache       map[*factoryInfo][]reflect.Value
+		buildLocks  map[*factoryInfo]*sync.Mutex
+		disposables []disposable
+		decorations decorations
+	}
+	// Scope は ScopeManaged のインスタンスを共有する解決の単位です。
+	// HTTP リクエストなど複数の Invoke にまたがる処理で使用し、終了時に Close します
+	Scope interface {
+		ServiceLocator
+		// Close はスコープが生成した ScopeManaged のインスタンスを生成と逆の順序で破棄します
+		Close(ctx context.Context) error
+	}
+)
+
+// BeginScope は ScopeManaged のインスタンスを共有するスコープを開始します。
+// ContainerManaged のインスタンスはコンテナから解決します。コンテナのオプションが正しくない場合、スコープの全ての操作は ContainerOptionsError を返します
+func (c *container) BeginScope() Scope {
+	return &scope{
+		container:  c,
+		cache:      make(map[*factoryInfo][]reflect.Value),
+		buildLocks: make(map[*factoryInfo]*sync.Mutex),
+	}
+}
+
+// Invoke はスコープからインスタンスを解決して呼び出します
+func (s *scope) Invoke(invoker Invoker) error {
+	return s.InvokeContext(context.Background(), invoker)
+}
+
+// InvokeContext は ctx を context.Context として解決できる状態で Invoke します
+func (s *scope) InvokeContext(ctx context.Context, invoker Invoker) error {
+	return s.container.invoke(newResolveContext(ctx, s), invoker)
+}
+
+// ResolveNamed はスコープから名前付きで登録されたコンポーネントを解決します
+func (s *scope) ResolveNamed(t reflect.Type, name string) (interface{}, error) {
+	return s.container.resolveNamed(newResolveContext(context.Background(), s), t, name)
+}
+
+// Verify はコンテナの登録内容をスコープから解決する前提で検証します
+func (s *scope) Verify() error {
+	return s.container.verify(s)
+}
+
+// WarmUp は全てのコンポーネントをスコープから解決します。ScopeManaged のインスタンスはスコープにキャッシュされます
+func (s *scope) WarmUp() error {
+	return s.container.warmUp(newResolveContext(context.Background(), s))
+}
+
+// Close はスコープが生成した ScopeManaged のインスタンスを生成と逆の順序で破棄し、キャッシュを破棄します。
+// ctx が終了した場合は中断し、破棄していないインスタンスは再び Close を呼び出した際に破棄します
+func (s *scope) Close(ctx context.Context) error {
+	if s.container.err != nil {
+		return s.container.err
+	}
+	s.mu.Lock()
+	ds := s.disposables
+	s.disposables = nil
+	s.cache = make(map[*factoryInfo][]reflect.Value)
+	s.mu.Unlock()
+	s.decorations.reset()
+	remaining, err := disposeAll(ctx, ds)
+	if len(remaining) > 0 {
+		s.mu.Lock()
+		s.disposables = append(remaining, s.disposables...)
+		s.mu.Unlock()
+	}
+	return err
+}
+
+func (s *scope) getCache(f *factoryInfo) ([]reflect.Value, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.cache[f]
+	return v, ok
+}
+func (s *scope) setCache(f *factoryInfo, v []reflect.Value, ds []disposable) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache[f] = v
+	s.disposables = append(s.disposables, ds...)
+}
+func (s *scope) getBuildLock(f *factoryInfo) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.buildLocks[f]
+	if !ok {
+		l = &sync.Mutex{}
+		s.buildLocks[f] = l
+	}
+	return l
+}
+func (s *scope) resolveScopeManagedObject(key componentKey, factoryInfo *factoryInfo, rc *resolveContext) ([]reflect.Value, error) {
+	if v, ok := s.getCache(factoryInfo); ok {
+		return v, nil
+	}
+	l := s.getBuildLock(factoryInfo)
+	if err := rc.lock(l); err != nil {
+		return nil, err
+	}
+	defer rc.unlock(l)
+	if v, ok := s.getCache(factoryInfo); ok {
+		return v, nil
+	}
+	outs, ds, err := s.container.construct(key, factoryInfo, rc)
+	if err != nil {
+		return nil, err
+	}
+	s.setCache(factoryInfo, outs, ds)
+	return outs, nil
+}
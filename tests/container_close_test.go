@@ -0,0 +1,329 @@
+package dijcttest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_container_Close(t *testing.T) {
+	service1Types := []reflect.Type{reflect.TypeOf((*Service1)(nil)).Elem()}
+	service2Types := []reflect.Type{reflect.TypeOf((*Service2)(nil)).Elem()}
+	service3Types := []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}
+	t.Run("ContainerManaged のインスタンスが依存関係の逆順に破棄されること", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		sut := dijct.NewContainer()
+		if err := sut.Register(func(service2 Service2) *closableService {
+			return NewClosableService("service1", log, nil)
+		}, dijct.RegisterOptions{Interfaces: service1Types, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(service3 Service3) *closableService {
+			return NewClosableService("service2", log, nil)
+		}, dijct.RegisterOptions{Interfaces: service2Types, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func() *closableService {
+			return NewClosableService("service3", log, nil)
+		}, dijct.RegisterOptions{Interfaces: service3Types, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1) {}); err != nil {
+			t.Fatal(err)
+		}
+		if len(log.Names()) != 0 {
+			t.Fatal(log.Names())
+		}
+		if err := sut.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if names := log.Names(); !reflect.DeepEqual(names, []string{"service1", "service2", "service3"}) {
+			t.Fatal(names)
+		}
+	})
+	t.Run("破棄に失敗しても全てのインスタンスを破棄してエラーをまとめて返すこと", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		e1 := errors.New("service1 error")
+		e2 := errors.New("service2 error")
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() *closableService {
+			return NewClosableService("service1", log, e1)
+		}, dijct.RegisterOptions{Interfaces: service1Types, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func() *closableService {
+			return NewClosableService("service2", log, e2)
+		}, dijct.RegisterOptions{Interfaces: service2Types, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func() *closableService {
+			return NewClosableService("service3", log, nil)
+		}, dijct.RegisterOptions{Interfaces: service3Types, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, service2 Service2, service3 Service3) {}); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Close(context.Background())
+		var de *dijct.DisposeError
+		if !errors.As(err, &de) || len(de.Errs) != 2 || !errors.Is(err, e1) || !errors.Is(err, e2) {
+			t.Fatal(err)
+		}
+		if len(log.Names()) != 3 {
+			t.Fatal(log.Names())
+		}
+	})
+	t.Run("Close 後は ContainerManaged のインスタンスが再生成されること", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() *closableService {
+			return NewClosableService("service1", log, nil)
+		}, dijct.RegisterOptions{Interfaces: service1Types, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		s1 := dijct.MustResolve[Service1](sut)
+		if err := sut.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if s2 := dijct.MustResolve[Service1](sut); s1.GetID() == s2.GetID() {
+			t.Fatal()
+		}
+		if err := sut.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if len(log.Names()) != 2 {
+			t.Fatal(log.Names())
+		}
+	})
+	t.Run("InvokeManaged のインスタンスは Invoke の終了時に破棄されること", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() *closableService {
+			return NewClosableService("service1", log, nil)
+		}, dijct.RegisterOptions{Interfaces: service1Types, LifetimeScope: dijct.InvokeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1) {
+			if len(log.Names()) != 0 {
+				t.Fatal(log.Names())
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if len(log.Names()) != 1 {
+			t.Fatal(log.Names())
+		}
+	})
+	t.Run("解決に失敗した場合も生成済みの InvokeManaged のインスタンスが破棄されること", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() *closableService {
+			return NewClosableService("service1", log, nil)
+		}, dijct.RegisterOptions{Interfaces: service1Types, LifetimeScope: dijct.InvokeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, service2 Service2) {}); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+		if len(log.Names()) != 1 {
+			t.Fatal(log.Names())
+		}
+	})
+	t.Run("Disposer を登録した場合は Disposer で破棄されること", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1, dijct.RegisterOptions{
+			LifetimeScope: dijct.ContainerManaged,
+			Disposer: func(instance interface{}) error {
+				log.Add(instance.(Service1).GetName())
+				return nil
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3(), dijct.RegisterOptions{Interfaces: service3Types}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, service3 Service3) {}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if names := log.Names(); !reflect.DeepEqual(names, []string{"service1"}) {
+			t.Fatal(names)
+		}
+	})
+	t.Run("コンテキストがキャンセルされた場合は破棄を中断し、再び Close した際に破棄すること", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() *closableService {
+			return NewClosableService("service1", log, nil)
+		}, dijct.RegisterOptions{Interfaces: service1Types, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		dijct.MustResolve[Service1](sut)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := sut.Close(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatal(err)
+		}
+		if len(log.Names()) != 0 {
+			t.Fatal(log.Names())
+		}
+		if err := sut.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if names := log.Names(); !reflect.DeepEqual(names, []string{"service1"}) {
+			t.Fatal(names)
+		}
+	})
+	t.Run("破棄の途中でコンテキストが終了した場合は残りを再び Close した際に破棄すること", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		ctx, cancel := context.WithCancel(context.Background())
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() *closableService {
+			return NewClosableService("service1", log, nil)
+		}, dijct.RegisterOptions{Interfaces: service1Types, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func() *closableService {
+			return NewClosableService("service2", log, nil)
+		}, dijct.RegisterOptions{Interfaces: service2Types, LifetimeScope: dijct.ContainerManaged, Disposer: func(instance interface{}) error {
+			cancel()
+			return instance.(*closableService).Close()
+		}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, service2 Service2) {}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Close(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatal(err)
+		}
+		if names := log.Names(); !reflect.DeepEqual(names, []string{"service2"}) {
+			t.Fatal(names)
+		}
+		if err := sut.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if names := log.Names(); !reflect.DeepEqual(names, []string{"service2", "service1"}) {
+			t.Fatal(names)
+		}
+	})
+}
+func Test_container_Cleanup(t *testing.T) {
+	service1Types := []reflect.Type{reflect.TypeOf((*Service1)(nil)).Elem()}
+	t.Run("InvokeManaged のクリーンアップ関数は Invoke の終了時に呼ばれること", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() (Service1, func(), error) {
+			return NewService1(), func() { log.Add("service1") }, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1) {
+			if len(log.Names()) != 0 {
+				t.Fatal(log.Names())
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if names := log.Names(); !reflect.DeepEqual(names, []string{"service1"}) {
+			t.Fatal(names)
+		}
+	})
+	t.Run("ContainerManaged のクリーンアップ関数は Close で呼ばれること", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		sut := dijct.NewContainer()
+		if err := sut.Register(func(service2 Service2) (Service1, func()) {
+			return NewService1(), func() { log.Add("service1") }
+		}, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func() (Service2, func(), error) {
+			return NewService2(), func() { log.Add("service2") }, nil
+		}, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1) {}); err != nil {
+			t.Fatal(err)
+		}
+		if len(log.Names()) != 0 {
+			t.Fatal(log.Names())
+		}
+		if err := sut.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if names := log.Names(); !reflect.DeepEqual(names, []string{"service1", "service2"}) {
+			t.Fatal(names)
+		}
+	})
+	t.Run("後続のコンストラクタが失敗した場合も生成済みのクリーンアップ関数が呼ばれること", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		e := errors.New("service2 error")
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() (Service1, func(), error) {
+			return NewService1(), func() { log.Add("service1") }, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(service1 Service1) (Service2, func(), error) {
+			return nil, nil, e
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service2 Service2) {}); !errors.Is(err, e) {
+			t.Fatal(err)
+		}
+		if names := log.Names(); !reflect.DeepEqual(names, []string{"service1"}) {
+			t.Fatal(names)
+		}
+	})
+	t.Run("クリーンアップ関数を返す場合は io.Closer で破棄しないこと", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() (*closableService, func()) {
+			return NewClosableService("closer", log, nil), func() { log.Add("cleanup") }
+		}, dijct.RegisterOptions{Interfaces: service1Types, LifetimeScope: dijct.InvokeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1) {}); err != nil {
+			t.Fatal(err)
+		}
+		if names := log.Names(); !reflect.DeepEqual(names, []string{"cleanup"}) {
+			t.Fatal(names)
+		}
+	})
+	t.Run("func() のみを返す関数はコンポーネントとして登録されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		called := false
+		if err := sut.Register(func() func() {
+			return func() { called = true }
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(fn func()) { fn() }); err != nil {
+			t.Fatal(err)
+		}
+		if !called {
+			t.Fatal()
+		}
+	})
+}
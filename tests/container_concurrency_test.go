@@ -0,0 +1,166 @@
+package dijcttest
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_container_Concurrency(t *testing.T) {
+	const goroutines = 50
+	t.Run("ContainerManaged のコンストラクタは並行に Invoke しても1度だけ呼ばれること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		var count int32
+		if err := sut.Register(func() Service1 {
+			atomic.AddInt32(&count, 1)
+			time.Sleep(10 * time.Millisecond)
+			return NewService1()
+		}, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([]string, goroutines)
+		errs := make([]error, goroutines)
+		var wg sync.WaitGroup
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = sut.Invoke(func(service1 Service1) {
+					ids[i] = service1.GetID()
+				})
+			}(i)
+		}
+		wg.Wait()
+		for i := 0; i < goroutines; i++ {
+			if errs[i] != nil {
+				t.Fatal(errs[i])
+			}
+			if ids[i] != ids[0] {
+				t.Fatal(ids[i], ids[0])
+			}
+		}
+		if count != 1 {
+			t.Fatal(count)
+		}
+	})
+	t.Run("依存先の ContainerManaged も並行に解決して1度だけ生成されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		var count int32
+		if err := sut.Register(NewNestedService); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func() Service2 {
+			atomic.AddInt32(&count, 1)
+			time.Sleep(10 * time.Millisecond)
+			return NewService2()
+		}, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3(), dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}}); err != nil {
+			t.Fatal(err)
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, goroutines)
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if i%2 == 0 {
+					errs[i] = sut.Invoke(func(nestedService NestedService) {})
+				} else {
+					errs[i] = sut.Invoke(func(service2 Service2) {})
+				}
+			}(i)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if count != 1 {
+			t.Fatal(count)
+		}
+	})
+	t.Run("Register と Invoke を並行に呼び出せること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		var wg sync.WaitGroup
+		errs := make([]error, goroutines)
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				switch i % 4 {
+				case 0:
+					errs[i] = sut.Register(NewService2, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged})
+				case 1:
+					errs[i] = sut.Register(NewService1)
+				case 2:
+					errs[i] = sut.Invoke(func(service1 Service1) {})
+				default:
+					sut.CreateChildContainer()
+					errs[i] = sut.Verify()
+				}
+			}(i)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	t.Run("循環する ContainerManaged を逆の端から並行に解決してもデッドロックせずにエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() Service3 {
+			time.Sleep(50 * time.Millisecond)
+			return NewService3()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(service3 Service3, service2 Service2) Service1 {
+			return NewService1()
+		}, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(service3 Service3, service1 Service1) Service2 {
+			return NewService2()
+		}, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+
+		errs := make(chan error, 2)
+		go func() {
+			errs <- sut.Invoke(func(service1 Service1) {})
+		}()
+		go func() {
+			errs <- sut.Invoke(func(service2 Service2) {})
+		}()
+		for i := 0; i < 2; i++ {
+			select {
+			case err := <-errs:
+				if !dijct.IsErrCircularDependency(err) {
+					t.Fatal(err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("deadlock")
+			}
+		}
+	})
+}
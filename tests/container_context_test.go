@@ -0,0 +1,78 @@
+package dijcttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+type contextKey struct{}
+
+func Test_container_InvokeContext(t *testing.T) {
+	t.Run("コンテキストを Invoke 先とコンストラクタで解決できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		var fromConstructor interface{}
+		if err := sut.Register(func(ctx context.Context) Service1 {
+			fromConstructor = ctx.Value(contextKey{})
+			return NewService1()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		ctx := context.WithValue(context.Background(), contextKey{}, "request")
+		if err := sut.InvokeContext(ctx, func(ctx context.Context, service1 Service1) {
+			if ctx.Value(contextKey{}) != "request" {
+				t.Fatal(ctx.Value(contextKey{}))
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if fromConstructor != "request" {
+			t.Fatal(fromConstructor)
+		}
+	})
+	t.Run("Invoke ではバックグラウンドのコンテキストが解決されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Invoke(func(ctx context.Context) {
+			if ctx != context.Background() {
+				t.Fatal(ctx)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("解決中にキャンセルされた場合は以降のコンストラクタを呼ばずに ctx.Err() を返すこと", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		sut := dijct.NewContainer()
+		ctx, cancel := context.WithCancel(context.Background())
+		if err := sut.Register(func(service2 Service2) Service1 {
+			log.Add("service1")
+			return NewService1()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func() (Service2, func()) {
+			cancel()
+			return NewService2(), func() { log.Add("service2 cleanup") }
+		}); err != nil {
+			t.Fatal(err)
+		}
+		invoked := false
+		err := sut.InvokeContext(ctx, func(service1 Service1) {
+			invoked = true
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatal(err)
+		}
+		if invoked {
+			t.Fatal()
+		}
+		if names := log.Names(); len(names) != 1 || names[0] != "service2 cleanup" {
+			t.Fatal(names)
+		}
+	})
+}
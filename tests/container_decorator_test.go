@@ -0,0 +1,211 @@
+package dijcttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_container_Decorate(t *testing.T) {
+	wrap := func(service1 Service1) Service1 {
+		return &service1Wrapper{Service1: service1}
+	}
+	t.Run("解決される全ての T に登録順に重ねて適用されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(service1 Service1) Service2 {
+			return &service2{id: service1.GetID(), name: service1.GetName()}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Decorate(wrap); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Decorate(func(service1 Service1) Service1 {
+			return &service1Wrapper{Service1: &service1Wrapper{Service1: service1}}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, service2 Service2) {
+			if service1.GetName() != "wrapped wrapped wrapped service1" {
+				t.Fatal(service1.GetName())
+			}
+			if service2.GetName() != service1.GetName() {
+				t.Fatal(service2.GetName())
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("デコレーターの依存先がコンテナから解決されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2); err != nil {
+			t.Fatal(err)
+		}
+		var decorated string
+		if err := sut.Decorate(func(service1 Service1, service2 Service2) (Service1, error) {
+			decorated = service2.GetID()
+			return service1, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, service2 Service2) {
+			if decorated != service2.GetID() {
+				t.Fatal(decorated)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("ContainerManaged の場合はデコレーターを1回だけ適用すること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		if err := sut.Decorate(func(service1 Service1) Service1 {
+			count++
+			return wrap(service1)
+		}); err != nil {
+			t.Fatal(err)
+		}
+		a := dijct.MustResolve[Service1](sut)
+		b := dijct.MustResolve[Service1](sut)
+		if count != 1 || a != b {
+			t.Fatal(count)
+		}
+	})
+	t.Run("子コンテナで登録したデコレーターは子コンテナにのみ適用されること", func(t *testing.T) {
+		t.Parallel()
+		parent := dijct.NewContainer()
+		if err := parent.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := parent.Decorate(wrap); err != nil {
+			t.Fatal(err)
+		}
+		sut := parent.CreateChildContainer()
+		if err := sut.Decorate(wrap); err != nil {
+			t.Fatal(err)
+		}
+		child := dijct.MustResolve[Service1](sut)
+		if child.GetName() != "wrapped wrapped service1" {
+			t.Fatal(child.GetName())
+		}
+		if child != dijct.MustResolve[Service1](sut) {
+			t.Fatal()
+		}
+		p := dijct.MustResolve[Service1](parent)
+		if p.GetName() != "wrapped service1" {
+			t.Fatal(p.GetName())
+		}
+		if child.(*service1Wrapper).Service1 != p {
+			t.Fatal()
+		}
+	})
+	t.Run("親コンテナの Close の後は子コンテナで新しいインスタンスに適用し直すこと", func(t *testing.T) {
+		t.Parallel()
+		parent := dijct.NewContainer()
+		if err := parent.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		sut := parent.CreateChildContainer()
+		if err := sut.Decorate(wrap); err != nil {
+			t.Fatal(err)
+		}
+		before := dijct.MustResolve[Service1](sut)
+		if err := parent.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		after := dijct.MustResolve[Service1](sut)
+		if after.GetID() == before.GetID() {
+			t.Fatal(after.GetID())
+		}
+		if after.(*service1Wrapper).Service1 != dijct.MustResolve[Service1](parent) {
+			t.Fatal()
+		}
+		if after != dijct.MustResolve[Service1](sut) {
+			t.Fatal()
+		}
+	})
+	t.Run("親コンテナで Decorate した後は子コンテナでも適用し直すこと", func(t *testing.T) {
+		t.Parallel()
+		parent := dijct.NewContainer()
+		if err := parent.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		sut := parent.CreateChildContainer()
+		if err := sut.Decorate(wrap); err != nil {
+			t.Fatal(err)
+		}
+		if v := dijct.MustResolve[Service1](sut); v.GetName() != "wrapped service1" {
+			t.Fatal(v.GetName())
+		}
+		if err := parent.Decorate(wrap); err != nil {
+			t.Fatal(err)
+		}
+		if v := dijct.MustResolve[Service1](parent); v.GetName() != "wrapped service1" {
+			t.Fatal(v.GetName())
+		}
+		if v := dijct.MustResolve[Service1](sut); v.GetName() != "wrapped wrapped service1" {
+			t.Fatal(v.GetName())
+		}
+	})
+	t.Run("デコレーターの形式が正しくない場合は登録できないこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		var e *dijct.RegistrationError
+		if err := sut.Decorate(func(service2 Service2) Service1 { return nil }); !errors.As(err, &e) || !errors.Is(err, dijct.ErrInvalidDecorator) {
+			t.Fatal(err)
+		}
+		if err := sut.Decorate(NewService1); !errors.Is(err, dijct.ErrInvalidDecorator) {
+			t.Fatal(err)
+		}
+		if err := sut.Decorate(wrap(nil)); !errors.Is(err, dijct.ErrRequireFunction) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("デコレーターのエラーが ConstructorError になること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		expected := errors.New("decorator error")
+		if err := sut.Decorate(func(service1 Service1) (Service1, error) { return nil, expected }); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Invoke(func(service1 Service1) {})
+		var e *dijct.ConstructorError
+		if !errors.As(err, &e) || !errors.Is(err, expected) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("デコレーターの依存先が登録されていない場合は Verify で検出されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Decorate(func(service1 Service1, service2 Service2) Service1 { return service1 }); err != nil {
+			t.Fatal(err)
+		}
+		var report *dijct.VerificationReport
+		if err := sut.Verify(); !errors.As(err, &report) || len(report.Entries()) != 1 {
+			t.Fatal(err)
+		}
+		if entry := report.Entries()[0]; entry.Err.Error() != "指定されたタイプを解決できません。(dijcttest.Service1 -> dijcttest.Service2): コンポーネントが登録されていません" {
+			t.Fatal(entry)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package dijcttest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_container_FieldInjection(t *testing.T) {
+	setup := func(t *testing.T) dijct.Container {
+		t.Helper()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, dijct.RegisterOptions{Name: "second"}); err != nil {
+			t.Fatal(err)
+		}
+		return sut
+	}
+	t.Run("構造体のタイプを登録するとタグを指定したフィールドに注入されること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t)
+		if err := sut.Register(reflect.TypeOf(TaggedService{})); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(s TaggedService, service1 Service1) {
+			if s.Service1 == nil || s.Service1.GetID() != service1.GetID() {
+				t.Fatal(s.Service1)
+			}
+			if s.Service2 == nil || s.Service2.GetName() != "service2" {
+				t.Fatal(s.Service2)
+			}
+			if s.Service3 != nil || s.Untagged != nil {
+				t.Fatal(s)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("コンストラクタが返す構造体のポインタのフィールドに注入されること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t)
+		if err := sut.Register(NewService3(), dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := dijct.Register[*TaggedService](sut, NewTaggedService); err != nil {
+			t.Fatal(err)
+		}
+		s, err := dijct.Resolve[*TaggedService](sut)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Service1 == nil || s.Service2 == nil || s.Service3 == nil || s.Untagged != nil {
+			t.Fatal(s)
+		}
+	})
+	t.Run("RegisterStruct で登録できること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t)
+		if err := dijct.RegisterStruct[TaggedService](sut); err != nil {
+			t.Fatal(err)
+		}
+		s, err := dijct.Resolve[TaggedService](sut)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Service1 == nil || s.Service2 == nil {
+			t.Fatal(s)
+		}
+	})
+	t.Run("必須のフィールドが登録されていない場合は解決できないこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := dijct.RegisterStruct[TaggedService](sut); err != nil {
+			t.Fatal(err)
+		}
+		_, err := dijct.Resolve[TaggedService](sut)
+		var e *dijct.ResolveError
+		if !errors.As(err, &e) || e.Name != "second" {
+			t.Fatal(err)
+		}
+		if err.Error() != "指定されたタイプを解決できません。(dijcttest.TaggedService -> dijcttest.Service2[second]): コンポーネントが登録されていません" {
+			t.Fatal(err)
+		}
+		var report *dijct.VerificationReport
+		if err := sut.Verify(); !errors.As(err, &report) || len(report.Entries()) != 1 {
+			t.Fatal(err)
+		}
+	})
+	t.Run("非公開のフィールドは UnexportedFields を指定した場合のみ注入されること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t)
+		err := dijct.RegisterStruct[unexportedTaggedService](sut)
+		if !errors.Is(err, dijct.ErrUnexportedField) {
+			t.Fatal(err)
+		}
+		if err := dijct.RegisterStruct[unexportedTaggedService](sut, dijct.RegisterOptions{UnexportedFields: true}); err != nil {
+			t.Fatal(err)
+		}
+		s, err := dijct.Resolve[unexportedTaggedService](sut)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.GetService1() == nil {
+			t.Fatal(s)
+		}
+	})
+	t.Run("タグの指定が正しくない場合は登録できないこと", func(t *testing.T) {
+		t.Parallel()
+		type invalid struct {
+			Service1 Service1 `dijct:"required"`
+		}
+		err := dijct.RegisterStruct[invalid](dijct.NewContainer())
+		var e *dijct.RegistrationError
+		if !errors.As(err, &e) || !errors.Is(err, dijct.ErrInvalidTag) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("構造体のポインタが nil の場合はエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t)
+		if err := dijct.Register[*TaggedService](sut, func() *TaggedService { return nil }); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := dijct.Resolve[*TaggedService](sut); !errors.Is(err, dijct.ErrNilStruct) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("フィールドへの注入に失敗した場合は生成したインスタンスを破棄すること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t)
+		cleaned := false
+		if err := dijct.Register[*TaggedService](sut, func() (*TaggedService, func(), error) {
+			return nil, func() { cleaned = true }, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		log := &CloseLog{}
+		if err := sut.Register(func() (*TaggedService, Service3) {
+			return nil, NewClosableService("closable", log, nil)
+		}, dijct.RegisterOptions{Name: "closable", Interfaces: []reflect.Type{reflect.TypeOf((*TaggedService)(nil))}}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := dijct.Resolve[*TaggedService](sut); !errors.Is(err, dijct.ErrNilStruct) {
+			t.Fatal(err)
+		}
+		if _, err := dijct.ResolveNamed[*TaggedService](sut, "closable"); !errors.Is(err, dijct.ErrNilStruct) {
+			t.Fatal(err)
+		}
+		if !cleaned || len(log.Names()) != 1 {
+			t.Fatal(cleaned, log.Names())
+		}
+	})
+}
@@ -0,0 +1,244 @@
+package dijcttest
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+type lazyHolder struct {
+	Service1 dijct.Lazy[Service1]
+	Service2 dijct.Lazy[Service2]
+}
+
+func Test_container_Lazy(t *testing.T) {
+	t.Run("Get を呼び出すまで生成されず、以降は同じインスタンスを返すこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		count := 0
+		if err := sut.Register(func() Service1 {
+			count++
+			return NewService1()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 dijct.Lazy[Service1]) {
+			if count != 0 {
+				t.Fatal(count)
+			}
+			a, err := service1.Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := service1.Get()
+			if count != 1 || a.GetID() != b.GetID() {
+				t.Fatal(count)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("T のライフタイムに従って解決されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(lazy1 dijct.Lazy[Service1], service1 Service1, lazy2 func() (Service2, error)) {
+			v, _ := lazy1.Get()
+			if v.GetID() != service1.GetID() {
+				t.Fatal()
+			}
+			v2, err := lazy2()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v2.GetID() != dijct.MustResolve[Service2](sut).GetID() {
+				t.Fatal()
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("func() (T, error) は呼び出す度に解決し、Transient の T を毎回生成すること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.Transient}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(provider func() (Service1, error), lazy dijct.Lazy[Service1]) {
+			a, err := provider()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := provider()
+			if a.GetID() == b.GetID() {
+				t.Fatal(a.GetID())
+			}
+			c, _ := lazy.Get()
+			d, _ := lazy.Get()
+			if c.GetID() != d.GetID() {
+				t.Fatal(c.GetID(), d.GetID())
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("コンストラクタのエラーを呼び出し時に返すこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1With2WithError); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 func() (Service1, error), service2 dijct.Lazy[Service2]) {
+			var e *dijct.ConstructorError
+			if _, err := service1(); !errors.As(err, &e) {
+				t.Fatal(err)
+			}
+			if _, err := service2.Get(); !errors.As(err, &e) {
+				t.Fatal(err)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("T が登録されていない場合は注入時にエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(func(service1 dijct.Lazy[Service1]) Service2 { return NewService2() }); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Invoke(func(service2 Service2) {})
+		if err == nil || err.Error() != "指定されたタイプを解決できません。(dijcttest.Service2 -> dijcttest.Service1): コンポーネントが登録されていません" {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Lazy で依存関係の循環を解消できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		var lazy dijct.Lazy[UseCase]
+		if err := sut.Register(NewUseCase); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewNestedService); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(useCase dijct.Lazy[UseCase]) Service1 {
+			lazy = useCase
+			return NewService1()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3(), dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(useCase UseCase) {
+			v, err := lazy.Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.GetID() != useCase.GetID() {
+				t.Fatal()
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("ゼロ値の Lazy はエラーを返すこと", func(t *testing.T) {
+		t.Parallel()
+		var lazy dijct.Lazy[Service1]
+		if _, err := lazy.Get(); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("複数の goroutine から並行に Get を呼び出せること", func(t *testing.T) {
+		t.Parallel()
+		const goroutines = 20
+		newContainer := func() dijct.Container {
+			sut := dijct.NewContainer()
+			if err := sut.Register(NewService3); err != nil {
+				t.Fatal(err)
+			}
+			if err := sut.Register(func(service3 Service3) Service1 {
+				return &service1{id: service3.GetID(), name: "service1"}
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if err := sut.Register(func(service3 Service3) Service2 {
+				return &service2{id: service3.GetID(), name: "service2"}
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if err := sut.Register(func(service1 dijct.Lazy[Service1], service2 dijct.Lazy[Service2]) lazyHolder {
+				return lazyHolder{Service1: service1, Service2: service2}
+			}, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+				t.Fatal(err)
+			}
+			return sut
+		}
+		getAll := func(holders []lazyHolder) []string {
+			ids := make([]string, len(holders)*2)
+			var wg sync.WaitGroup
+			for i, holder := range holders {
+				wg.Add(2)
+				go func(i int, holder lazyHolder) {
+					defer wg.Done()
+					v, err := holder.Service1.Get()
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					ids[i*2] = v.GetID()
+				}(i, holder)
+				go func(i int, holder lazyHolder) {
+					defer wg.Done()
+					v, err := holder.Service2.Get()
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					ids[i*2+1] = v.GetID()
+				}(i, holder)
+			}
+			wg.Wait()
+			return ids
+		}
+
+		// Invoke の終了後に呼び出す場合
+		holders := make([]lazyHolder, goroutines)
+		for i := range holders {
+			holders[i] = dijct.MustResolve[lazyHolder](newContainer())
+		}
+		for _, id := range getAll(holders) {
+			if id == "" {
+				t.Fatal()
+			}
+		}
+
+		// Invoke の中で呼び出す場合は InvokeManaged のインスタンスを共有すること
+		if err := newContainer().Invoke(func(holder lazyHolder, service3 Service3) {
+			for _, id := range getAll([]lazyHolder{holder}) {
+				if id != service3.GetID() {
+					t.Fatal(id)
+				}
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
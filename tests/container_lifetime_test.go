@@ -0,0 +1,139 @@
+package dijcttest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_container_CaptiveDependency(t *testing.T) {
+	nestedServiceType := reflect.TypeOf((*NestedService)(nil)).Elem()
+	service1Type := reflect.TypeOf((*Service1)(nil)).Elem()
+	register := func(t *testing.T, sut dijct.Container, nestedServiceScope, service1Scope dijct.LifetimeScope) {
+		t.Helper()
+		if err := sut.Register(NewNestedService, dijct.RegisterOptions{LifetimeScope: nestedServiceScope}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, dijct.RegisterOptions{LifetimeScope: service1Scope}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, dijct.RegisterOptions{LifetimeScope: dijct.Transient}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3(), dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Run("Verify は ContainerManaged から InvokeManaged への依存を CaptiveDependencyError で返すこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		register(t, sut, dijct.ContainerManaged, dijct.InvokeManaged)
+		err := sut.Verify()
+		var e *dijct.CaptiveDependencyError
+		if !errors.As(err, &e) || !dijct.IsErrCaptiveDependency(err) {
+			t.Fatal(err)
+		}
+		if e.Type != nestedServiceType || e.LifetimeScope != dijct.ContainerManaged {
+			t.Fatal(e)
+		}
+		if e.Dependency != service1Type || e.DependencyLifetimeScope != dijct.InvokeManaged {
+			t.Fatal(e)
+		}
+		if e.Error() != "ライフタイムの短いコンポーネントに依存しています。(dijcttest.NestedService(ContainerManaged) -> dijcttest.Service1(InvokeManaged))" {
+			t.Fatal(err)
+		}
+	})
+	t.Run("StrictLifetime でない場合は Invoke できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		register(t, sut, dijct.ContainerManaged, dijct.InvokeManaged)
+		if err := sut.Invoke(func(nestedService NestedService) {}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("ScopeManaged から InvokeManaged への依存を検出すること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		register(t, sut, dijct.ScopeManaged, dijct.InvokeManaged)
+		if err := sut.Verify(); !dijct.IsErrCaptiveDependency(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("ContainerManaged から Transient への依存を検出すること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		register(t, sut, dijct.ContainerManaged, dijct.Transient)
+		if err := sut.Verify(); !dijct.IsErrCaptiveDependency(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("ライフタイムが同じか長いコンポーネントへの依存は検出しないこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{StrictLifetime: true})
+		register(t, sut, dijct.InvokeManaged, dijct.ScopeManaged)
+		if err := sut.Verify(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("StrictLifetime の場合は依存先が短いライフタイムで登録済みであれば登録できないこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{StrictLifetime: true})
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Register(NewNestedService, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged})
+		var re *dijct.RegistrationError
+		var e *dijct.CaptiveDependencyError
+		if !errors.As(err, &re) || !errors.As(err, &e) {
+			t.Fatal(err)
+		}
+		if re.Type != nestedServiceType || e.Type != nestedServiceType || e.Dependency != service1Type {
+			t.Fatal(err)
+		}
+	})
+	t.Run("StrictLifetime の場合は登録済みのコンポーネントより短いライフタイムの依存先を登録できないこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{StrictLifetime: true})
+		if err := sut.Register(NewNestedService, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Register(NewService1)
+		var re *dijct.RegistrationError
+		var e *dijct.CaptiveDependencyError
+		if !errors.As(err, &re) || !errors.As(err, &e) {
+			t.Fatal(err)
+		}
+		if re.Type != service1Type || e.Type != nestedServiceType || e.Dependency != service1Type {
+			t.Fatal(err)
+		}
+	})
+	t.Run("StrictLifetime の場合は集合の要素への依存も検出すること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{StrictLifetime: true})
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Multiple: true, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Multiple: true, LifetimeScope: dijct.InvokeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Register(func(services []Service1) Service2 {
+			return NewService2()
+		}, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged})
+		if !dijct.IsErrCaptiveDependency(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("StrictLifetime は子コンテナに引き継がれること", func(t *testing.T) {
+		t.Parallel()
+		parent := dijct.NewContainer(dijct.ContainerOptions{StrictLifetime: true})
+		if err := parent.Register(NewNestedService, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		sut := parent.CreateChildContainer()
+		if err := sut.Register(NewService1); !dijct.IsErrCaptiveDependency(err) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package dijcttest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_container_Multiple(t *testing.T) {
+	service1Types := []reflect.Type{reflect.TypeOf((*Service1)(nil)).Elem()}
+	t.Run("Multiple で登録したコンポーネントを登録順にスライスで解決できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Multiple: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, dijct.RegisterOptions{Multiple: true, Interfaces: service1Types}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3(), dijct.RegisterOptions{Multiple: true, Interfaces: service1Types}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(services []Service1, service1 Service1) {
+			if len(services) != 3 {
+				t.Fatal(len(services))
+			}
+			if services[0].GetName() != "service1" || services[1].GetName() != "service2" || services[2].GetName() != "service3" {
+				t.Fatal(services[0].GetName(), services[1].GetName(), services[2].GetName())
+			}
+			if service1.GetName() != "service3" {
+				t.Fatal(service1.GetName())
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Multiple を指定しない登録は上書きされること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Multiple: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, dijct.RegisterOptions{Interfaces: service1Types}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(services []Service1) {
+			if len(services) != 1 || services[0].GetName() != "service2" {
+				t.Fatal(services)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("名前付きの登録を map で解決できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Name: "a"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, dijct.RegisterOptions{Name: "b", Interfaces: service1Types}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3(), dijct.RegisterOptions{Interfaces: service1Types}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(services map[string]Service1, all []Service1) {
+			if len(services) != 2 || services["a"].GetName() != "service1" || services["b"].GetName() != "service2" {
+				t.Fatal(services)
+			}
+			if len(all) != 3 {
+				t.Fatal(len(all))
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("各要素はそれぞれのライフタイムで解決されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Multiple: true, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Multiple: true, LifetimeScope: dijct.InvokeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		if err := sut.Invoke(func(services []Service1) {
+			ids = []string{services[0].GetID(), services[1].GetID()}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(services []Service1) {
+			if ids[0] != services[0].GetID() || ids[1] == services[1].GetID() {
+				t.Fatal(ids, services[0].GetID(), services[1].GetID())
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("要素が登録されていない場合は解決できないこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Invoke(func(services []Service1) {}); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(services map[string]Service1) {}); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("スライス自体を登録した場合はその登録が優先されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Multiple: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func() []Service1 { return nil }); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(services []Service1) {
+			if services != nil {
+				t.Fatal(services)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package dijcttest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_container_Named(t *testing.T) {
+	service1Type := reflect.TypeOf((*Service1)(nil)).Elem()
+	t.Run("同じタイプを名前付きで複数登録できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Name: "primary", LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2(), dijct.RegisterOptions{Name: "replica", Interfaces: []reflect.Type{service1Type}}); err != nil {
+			t.Fatal(err)
+		}
+		primary, err := dijct.ResolveNamed[Service1](sut, "primary")
+		if err != nil {
+			t.Fatal(err)
+		}
+		replica, err := sut.ResolveNamed(service1Type, "replica")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if primary.GetName() != "service1" || replica.(Service1).GetName() != "service2" {
+			t.Fatal(primary.GetName(), replica.(Service1).GetName())
+		}
+		if _, err := dijct.Resolve[Service1](sut); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("名前付きの登録はそれぞれのライフタイムとキャッシュを持つこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Name: "primary", LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Name: "replica", LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		p1 := dijct.MustResolve[Service1](sut)
+		p2 := dijct.MustResolve[Service1](sut)
+		primary1, _ := dijct.ResolveNamed[Service1](sut, "primary")
+		primary2, _ := dijct.ResolveNamed[Service1](sut, "primary")
+		replica, _ := dijct.ResolveNamed[Service1](sut, "replica")
+		if p1.GetID() == p2.GetID() {
+			t.Fatal()
+		}
+		if primary1.GetID() != primary2.GetID() || primary1.GetID() == replica.GetID() {
+			t.Fatal(primary1.GetID(), primary2.GetID(), replica.GetID())
+		}
+	})
+	t.Run("名前付きの登録が名前なしの同じタイプに依存できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(service1 Service1) Service1 {
+			return &service1Wrapper{Service1: service1}
+		}, dijct.RegisterOptions{Name: "wrapped"}); err != nil {
+			t.Fatal(err)
+		}
+		v, err := dijct.ResolveNamed[Service1](sut, "wrapped")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.GetName() != "wrapped service1" {
+			t.Fatal(v.GetName())
+		}
+	})
+	t.Run("名前付きのコンポーネントを解決できない場合は名前がエラーに含まれること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		_, err := dijct.ResolveNamed[Service1](sut, "primary")
+		var e *dijct.ResolveError
+		if !errors.As(err, &e) || e.Name != "primary" || e.Type != service1Type {
+			t.Fatal(err)
+		}
+		if err.Error() != "指定されたタイプを解決できません。(dijcttest.Service1[primary]): コンポーネントが登録されていません" {
+			t.Fatal(err)
+		}
+	})
+}
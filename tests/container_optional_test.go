@@ -0,0 +1,79 @@
+package dijcttest
+
+import (
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_container_Optional(t *testing.T) {
+	t.Run("登録がない場合は Present が false で解決されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, service2 dijct.Optional[Service2]) {
+			if service2.Present || service2.Value != nil {
+				t.Fatal(service2)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("登録がある場合は Value に設定されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		service1, err := dijct.Resolve[dijct.Optional[Service1]](sut)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !service1.Present || service1.Value.GetID() != dijct.MustResolve[Service1](sut).GetID() {
+			t.Fatal(service1)
+		}
+	})
+	t.Run("コンストラクタの引数と名前付きのフィールドに指定できること", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			dijct.In
+			Service2 dijct.Optional[Service2] `dijct:"name=second"`
+			Service3 dijct.Optional[Service3] `dijct:"name=third"`
+		}
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService2, dijct.RegisterOptions{Name: "second"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(service1 dijct.Optional[Service1], p params) NestedService {
+			return NewNestedService(service1.Value, p.Service2.Value, p.Service3.Value)
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(nestedService NestedService) {
+			if nestedService.GetService1() != nil || nestedService.GetService2() == nil || nestedService.GetService3() != nil {
+				t.Fatal(nestedService)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("登録されたコンポーネントの依存先が解決できない場合はエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewNestedService); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Invoke(func(nestedService dijct.Optional[NestedService]) {})
+		if !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+		if err.Error() != "指定されたタイプを解決できません。(dijcttest.NestedService -> dijcttest.Service1): コンポーネントが登録されていません" {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package dijcttest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_container_Options(t *testing.T) {
+	service1Type := reflect.TypeOf((*Service1)(nil)).Elem()
+	t.Run("オプションを複数指定した場合は登録と解決が失敗すること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{}, dijct.ContainerOptions{})
+		var e *dijct.RegistrationError
+		if err := sut.Register(NewService1); !errors.As(err, &e) || !errors.Is(err, dijct.ErrNoMultipleOption) {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(ctx context.Context) {}); !errors.Is(err, dijct.ErrNoMultipleOption) {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(); !errors.Is(err, dijct.ErrNoMultipleOption) {
+			t.Fatal(err)
+		}
+		if err := sut.CreateChildContainer().Register(NewService1); !errors.Is(err, dijct.ErrNoMultipleOption) {
+			t.Fatal(err)
+		}
+		var oe *dijct.ContainerOptionsError
+		if err := sut.Invoke(func(ctx context.Context) {}); !errors.As(err, &oe) {
+			t.Fatal(err)
+		}
+		if _, err := dijct.Resolve[context.Context](sut); !errors.As(err, &oe) {
+			t.Fatal(err)
+		}
+		if err := sut.WarmUp(); !errors.As(err, &oe) {
+			t.Fatal(err)
+		}
+		scope := sut.BeginScope()
+		if err := scope.Invoke(func(ctx context.Context) {}); !errors.As(err, &oe) {
+			t.Fatal(err)
+		}
+		if err := scope.Close(context.Background()); !errors.As(err, &oe) {
+			t.Fatal(err)
+		}
+		if err := sut.Close(context.Background()); !errors.As(err, &oe) {
+			t.Fatal(err)
+		}
+		if sut.Has(service1Type) || sut.Registrations() != nil || len(sut.Graph().Nodes) != 0 {
+			t.Fatal()
+		}
+	})
+	t.Run("DefaultLifetimeScope をオプションを指定しない登録のライフタイムにすること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{DefaultLifetimeScope: dijct.ContainerManaged})
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, dijct.RegisterOptions{LifetimeScope: dijct.InvokeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if dijct.MustResolve[Service1](sut).GetID() != dijct.MustResolve[Service1](sut).GetID() {
+			t.Fatal()
+		}
+		if dijct.MustResolve[Service2](sut).GetID() == dijct.MustResolve[Service2](sut).GetID() {
+			t.Fatal()
+		}
+		if r := sut.CreateChildContainer().Registrations(); r[0].LifetimeScope != dijct.ContainerManaged {
+			t.Fatal(r)
+		}
+	})
+	t.Run("DefaultLifetimeScope が正しくない場合は ContainerOptionsError になること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{DefaultLifetimeScope: dijct.LifetimeScope(100)})
+		var e *dijct.ContainerOptionsError
+		if err := sut.Register(NewService1); !errors.As(err, &e) || !errors.Is(err, dijct.ErrInvalidLifetimeScope) {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(ctx context.Context) {}); !errors.As(err, &e) || !errors.Is(err, dijct.ErrInvalidLifetimeScope) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("DefaultLifetimeScope を指定しない場合は InvokeManaged になること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{})
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if r := sut.Registrations(); r[0].LifetimeScope != dijct.InvokeManaged {
+			t.Fatal(r)
+		}
+	})
+	t.Run("LifetimeScope を指定せずにオプションを指定した登録にも DefaultLifetimeScope を使用すること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{DefaultLifetimeScope: dijct.ContainerManaged})
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Name: "a"}); err != nil {
+			t.Fatal(err)
+		}
+		a, err := dijct.ResolveNamed[Service1](sut, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := dijct.ResolveNamed[Service1](sut, "a")
+		if a.GetID() != b.GetID() {
+			t.Fatal()
+		}
+		if r := sut.Registrations(); r[0].LifetimeScope != dijct.ContainerManaged {
+			t.Fatal(r)
+		}
+	})
+	t.Run("DisableAutoInjection の場合はコンテナを注入しないこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{DisableAutoInjection: true})
+		if err := sut.Invoke(func(locator dijct.ServiceLocator) {}); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+		if err := sut.BeginScope().Invoke(func(scope dijct.Scope) {}); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(c dijct.Container) Service1 { return NewService1() }); err != nil {
+			t.Fatal(err)
+		}
+		var report *dijct.VerificationReport
+		if err := sut.Verify(); !errors.As(err, &report) || report.Entries()[0].Type != reflect.TypeOf((*dijct.Container)(nil)).Elem() {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(ctx context.Context) {}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Hook に登録とコンストラクタの呼び出しを通知すること", func(t *testing.T) {
+		t.Parallel()
+		var events []dijct.Event
+		sut := dijct.NewContainer(dijct.ContainerOptions{Hook: func(event dijct.Event) {
+			events = append(events, event)
+		}})
+		expected := errors.New("constructor error")
+		if err := sut.Register(func() (Service1, error) { return nil, expected }); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1) {}); !errors.Is(err, expected) {
+			t.Fatal(err)
+		}
+		if err := sut.Replace(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1) {}); err != nil {
+			t.Fatal(err)
+		}
+		sut.Unregister(service1Type)
+
+		kinds := []dijct.EventKind{dijct.EventRegistered, dijct.EventConstructed, dijct.EventReplaced, dijct.EventConstructed, dijct.EventUnregistered}
+		if len(events) != len(kinds) {
+			t.Fatal(events)
+		}
+		for i, kind := range kinds {
+			if events[i].Kind != kind || events[i].Type != service1Type {
+				t.Fatal(i, events[i])
+			}
+		}
+		if !errors.Is(events[1].Err, expected) || events[3].Err != nil {
+			t.Fatal(events)
+		}
+		if events[3].Constructor != "github.com/wakuwaku3/dijct/tests.NewService1" || events[3].LifetimeScope != dijct.InvokeManaged {
+			t.Fatal(events[3])
+		}
+	})
+	t.Run("Hook の中で生成中のコンポーネントを解決できること", func(t *testing.T) {
+		t.Parallel()
+		var sut dijct.Container
+		var resolved []string
+		sut = dijct.NewContainer(dijct.ContainerOptions{Hook: func(event dijct.Event) {
+			if event.Kind != dijct.EventConstructed {
+				return
+			}
+			// Service1 は Service2 の依存先として生成するため、Service2 のロックを保持している間に通知しないこと
+			resolved = append(resolved, dijct.MustResolve[Service2](sut).GetID())
+		}})
+		lts := dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}
+		if err := sut.Register(NewService1, lts); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(service1 Service1) Service2 {
+			return &service2{id: service1.GetID(), name: "service2"}
+		}, lts); err != nil {
+			t.Fatal(err)
+		}
+		done := make(chan Service2)
+		go func() {
+			done <- dijct.MustResolve[Service2](sut)
+		}()
+		select {
+		case service2 := <-done:
+			if len(resolved) != 2 || resolved[0] != service2.GetID() || resolved[1] != service2.GetID() {
+				t.Fatal(resolved)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("deadlock")
+		}
+	})
+}
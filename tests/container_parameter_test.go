@@ -0,0 +1,167 @@
+package dijcttest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+type (
+	nestedServiceParams struct {
+		dijct.In
+		Service1 Service1
+		Service2 Service2 `dijct:"name=second"`
+		Service3 Service3 `dijct:"optional"`
+	}
+	service1With2Results struct {
+		dijct.Out
+		Service1 Service1
+		Service2 Service2 `dijct:"name=second"`
+	}
+	service3Results struct {
+		dijct.Out
+		A Service3 `dijct:"name=a"`
+		B Service3 `dijct:"name=b"`
+		C Service3 `dijct:"name=c"`
+		D Service3 `dijct:"name=d"`
+	}
+)
+
+func Test_container_ParameterObject(t *testing.T) {
+	setup := func(t *testing.T) dijct.Container {
+		t.Helper()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, dijct.RegisterOptions{Name: "second"}); err != nil {
+			t.Fatal(err)
+		}
+		return sut
+	}
+	t.Run("In を埋め込んだ引数はフィールドごとに解決されること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t)
+		if err := sut.Register(func(p nestedServiceParams) NestedService {
+			return NewNestedService(p.Service1, p.Service2, p.Service3)
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(nestedService NestedService, service1 Service1) {
+			if nestedService.GetService1().GetID() != service1.GetID() {
+				t.Fatal()
+			}
+			if nestedService.GetService2() == nil || nestedService.GetService3() != nil {
+				t.Fatal(nestedService)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Invoke の引数に In を埋め込んだ構造体を指定できること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t)
+		if err := sut.Invoke(func(p nestedServiceParams, service1 Service1) {
+			if p.Service1.GetID() != service1.GetID() || p.Service2 == nil || p.Service3 != nil {
+				t.Fatal(p)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Out を埋め込んだ返り値はフィールドごとに1回の呼び出しで登録されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		count := 0
+		if err := sut.Register(func() service1With2Results {
+			count++
+			service1, service2 := NewService1With2()
+			return service1With2Results{Service1: service1, Service2: service2}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(p nestedServiceParams) {
+			if p.Service1 == nil || p.Service2 == nil {
+				t.Fatal(p)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Fatal(count)
+		}
+		if _, err := dijct.Resolve[Service2](sut); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Out のフィールドは []T でフィールドの順に解決されること", func(t *testing.T) {
+		t.Parallel()
+		for i := 0; i < 30; i++ {
+			sut := dijct.NewContainer()
+			if err := sut.Register(func() service3Results {
+				return service3Results{
+					A: &service3{name: "a"},
+					B: &service3{name: "b"},
+					C: &service3{name: "c"},
+					D: &service3{name: "d"},
+				}
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if err := sut.Invoke(func(services []Service3) {
+				names := ""
+				for _, s := range services {
+					names += s.GetName()
+				}
+				if names != "abcd" {
+					t.Fatal(names)
+				}
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	t.Run("必須のフィールドが登録されていない場合は Verify で検出されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(func(p nestedServiceParams) NestedService {
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		var report *dijct.VerificationReport
+		if err := sut.Verify(); !errors.As(err, &report) {
+			t.Fatal(err)
+		}
+		entries := report.Entries()
+		if len(entries) != 2 || entries[0].Err.Error() != "指定されたタイプを解決できません。(dijcttest.NestedService -> dijcttest.Service1): コンポーネントが登録されていません" {
+			t.Fatal(report)
+		}
+		if entries[1].Name != "second" {
+			t.Fatal(entries[1])
+		}
+	})
+	t.Run("非公開のフィールドがある場合はエラーになること", func(t *testing.T) {
+		t.Parallel()
+		type params struct {
+			dijct.In
+			service1 Service1
+		}
+		type results struct {
+			dijct.Out
+			service1 Service1
+		}
+		sut := setup(t)
+		if err := sut.Invoke(func(p params) {}); !errors.Is(err, dijct.ErrUnexportedParameterField) {
+			t.Fatal(err)
+		}
+		var e *dijct.RegistrationError
+		if err := sut.Register(func(p params) Service3 { return nil }); !errors.As(err, &e) || !errors.Is(err, dijct.ErrUnexportedParameterField) {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func() results { return results{} }); !errors.Is(err, dijct.ErrUnexportedParameterField) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package dijcttest
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_container_Registrations(t *testing.T) {
+	service1Type := reflect.TypeOf((*Service1)(nil)).Elem()
+	service2Type := reflect.TypeOf((*Service2)(nil)).Elem()
+	service3Type := reflect.TypeOf((*Service3)(nil)).Elem()
+	t.Run("Has で親コンテナも含めて登録されているかを判定できること", func(t *testing.T) {
+		t.Parallel()
+		parent := dijct.NewContainer()
+		if parent.Has(service1Type) {
+			t.Fatal()
+		}
+		if err := parent.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := parent.Register(NewService2, dijct.RegisterOptions{Name: "named"}); err != nil {
+			t.Fatal(err)
+		}
+		sut := parent.CreateChildContainer()
+		if !sut.Has(service1Type) || sut.Has(service2Type) {
+			t.Fatal()
+		}
+	})
+	t.Run("Unregister で登録を削除できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{StrictRegistration: true})
+		if err := sut.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		before := dijct.MustResolve[Service1](sut)
+		sut.Unregister(service1Type)
+		if sut.Has(service1Type) {
+			t.Fatal()
+		}
+		if _, err := dijct.Resolve[Service1](sut); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if dijct.MustResolve[Service1](sut).GetID() == before.GetID() {
+			t.Fatal()
+		}
+	})
+	t.Run("名前付きの登録は HasNamed と UnregisterNamed で扱うこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Name: "p"}); err != nil {
+			t.Fatal(err)
+		}
+		if sut.Has(service1Type) || !sut.HasNamed(service1Type, "p") {
+			t.Fatal()
+		}
+		sut.Unregister(service1Type)
+		if _, err := dijct.ResolveNamed[Service1](sut, "p"); err != nil {
+			t.Fatal(err)
+		}
+		sut.UnregisterNamed(service1Type, "p")
+		if sut.HasNamed(service1Type, "p") {
+			t.Fatal()
+		}
+		if _, err := dijct.ResolveNamed[Service1](sut, "p"); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("子コンテナの Unregister は親コンテナの登録を削除しないこと", func(t *testing.T) {
+		t.Parallel()
+		parent := dijct.NewContainer()
+		if err := parent.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		sut := parent.CreateChildContainer()
+		sut.Unregister(service1Type)
+		if !sut.Has(service1Type) || !parent.Has(service1Type) {
+			t.Fatal()
+		}
+	})
+	t.Run("Registrations で登録内容の一覧を取得できること", func(t *testing.T) {
+		t.Parallel()
+		parent := dijct.NewContainer()
+		if err := parent.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		sut := parent.CreateChildContainer()
+		if err := dijct.Provide[Service2](sut, NewService2()); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3, dijct.RegisterOptions{Name: "named"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewNestedService); err != nil {
+			t.Fatal(err)
+		}
+		dijct.MustResolve[Service1](sut)
+
+		registrations := sut.Registrations()
+		if len(registrations) != 4 {
+			t.Fatal(registrations)
+		}
+		r := registrations[0]
+		if r.Type != service1Type || r.LifetimeScope != dijct.ContainerManaged || !r.Cached || !r.Inherited {
+			t.Fatal(r)
+		}
+		if r.Constructor != "github.com/wakuwaku3/dijct/tests.NewService1" || filepath.Base(r.File) != "mock.go" || r.Line != 151 {
+			t.Fatal(r)
+		}
+		expected := fmt.Sprintf("dijcttest.Service1(ContainerManaged) <- github.com/wakuwaku3/dijct/tests.NewService1 (%s:151) [cached]", r.File)
+		if r.String() != expected {
+			t.Fatal(r.String())
+		}
+		if r := registrations[1]; r.Type != service2Type || r.Constructor != "*dijcttest.service2" || r.File != "" || r.Cached {
+			t.Fatal(r)
+		}
+		if r := registrations[2]; r.Type != service3Type || r.Name != "named" {
+			t.Fatal(r)
+		}
+		r = registrations[3]
+		if !reflect.DeepEqual(r.Dependencies, []reflect.Type{service1Type, service2Type, service3Type}) || r.Inherited {
+			t.Fatal(r)
+		}
+	})
+}
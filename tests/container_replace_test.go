@@ -0,0 +1,124 @@
+package dijcttest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_container_Replace(t *testing.T) {
+	fake := &service1{id: "fake", name: "fake"}
+	t.Run("StrictRegistration の場合は同じタイプを再び登録できないこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{StrictRegistration: true})
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		var e *dijct.RegistrationError
+		if err := sut.Register(NewService1); !errors.As(err, &e) || !errors.Is(err, dijct.ErrDuplicateRegistration) {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Name: "replica"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Multiple: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.CreateChildContainer().Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("StrictRegistration でない場合は後の登録で上書きすること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := dijct.Provide[Service1](sut, fake); err != nil {
+			t.Fatal(err)
+		}
+		if dijct.MustResolve[Service1](sut) != fake {
+			t.Fatal()
+		}
+	})
+	t.Run("Replace で置き換えた場合はキャッシュを破棄して置き換えた登録から解決すること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer(dijct.ContainerOptions{StrictRegistration: true})
+		if err := sut.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewNestedService, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if dijct.MustResolve[NestedService](sut).GetService1().GetName() != "service1" {
+			t.Fatal()
+		}
+		if err := dijct.Replace[Service1](sut, fake); err != nil {
+			t.Fatal(err)
+		}
+		if dijct.MustResolve[Service1](sut) != fake {
+			t.Fatal()
+		}
+		// NestedService のキャッシュは置き換えていないため破棄しません
+		if dijct.MustResolve[NestedService](sut).GetService1().GetName() != "service1" {
+			t.Fatal()
+		}
+	})
+	t.Run("Replace で置き換えた登録が記録されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, dijct.RegisterOptions{Multiple: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := dijct.Replace[Service1](sut, fake); err != nil {
+			t.Fatal(err)
+		}
+		nodes := sut.Graph().Nodes
+		expected := []string{"github.com/wakuwaku3/dijct/tests.NewService1", "github.com/wakuwaku3/dijct/tests.NewService1"}
+		if len(nodes) != 1 || !reflect.DeepEqual(nodes[0].Replaces, expected) {
+			t.Fatal(nodes)
+		}
+	})
+	t.Run("子コンテナの Replace は親コンテナの登録を変更しないこと", func(t *testing.T) {
+		t.Parallel()
+		parent := dijct.NewContainer(dijct.ContainerOptions{StrictRegistration: true})
+		if err := parent.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		sut := parent.CreateChildContainer()
+		if err := dijct.Replace[Service1](sut, fake); err != nil {
+			t.Fatal(err)
+		}
+		if dijct.MustResolve[Service1](sut) != fake || dijct.MustResolve[Service1](parent).GetName() != "service1" {
+			t.Fatal()
+		}
+		if nodes := sut.Graph().Nodes; len(nodes) != 1 || !reflect.DeepEqual(nodes[0].Replaces, []string{"github.com/wakuwaku3/dijct/tests.NewService1"}) {
+			t.Fatal(nodes)
+		}
+	})
+	t.Run("登録されていないタイプは Replace できないこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		var e *dijct.RegistrationError
+		if err := dijct.Replace[Service1](sut, fake); !errors.As(err, &e) || !errors.Is(err, dijct.ErrNotRegisteredComponent) {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Replace(NewService1, dijct.RegisterOptions{Multiple: true}); !errors.Is(err, dijct.ErrMultipleReplace) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package dijcttest
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"testing"
@@ -14,8 +15,16 @@ func Test_container_Invoke(t *testing.T) {
 		if err := sut.Register(NewService1With2WithError); err != nil {
 			t.Fatal()
 		}
-		if err := sut.Invoke(func(service1 Service1) {}); err == nil || err.Error() != "NewService1With2WithError Error" {
-			t.Fatal()
+		err := sut.Invoke(func(service1 Service1) {})
+		var e *dijct.ConstructorError
+		if !errors.As(err, &e) || e.Err.Error() != "NewService1With2WithError Error" {
+			t.Fatal(err)
+		}
+		if e.Type != reflect.TypeOf((*Service1)(nil)).Elem() {
+			t.Fatal(e.Type)
+		}
+		if err.Error() != "コンストラクタがエラーを返しました。(dijcttest.Service1): NewService1With2WithError Error" {
+			t.Fatal(err)
 		}
 	})
 	t.Skip()
@@ -124,6 +133,20 @@ func Test_container_Invoke(t *testing.T) {
 			t.Fatal(err)
 		}
 	})
+	t.Run("LifetimeScope を指定せずに Interfaces のみ指定した場合はコンテナの既定のライフタイムになること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		ifs := []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}
+		if err := sut.Register(func() *service3 { return NewService3().(*service3) }, dijct.RegisterOptions{Interfaces: ifs}); err != nil {
+			t.Fatal(err)
+		}
+		if dijct.MustResolve[Service3](sut).GetID() == dijct.MustResolve[Service3](sut).GetID() {
+			t.Fatal()
+		}
+		if r := sut.Registrations(); r[0].LifetimeScope != dijct.InvokeManaged {
+			t.Fatal(r)
+		}
+	})
 	t.Run("コンテナインスタンス自身を自己解決できること", func(t *testing.T) {
 		t.Parallel()
 		sut := dijct.NewContainer()
@@ -181,7 +204,7 @@ func Test_container_Invoke(t *testing.T) {
 		})
 	})
 	t.Run("コンストラクタの戻り値が複数の場合", func(t *testing.T) {
-		t.Run("先頭の引数が解決される", func(t *testing.T) {
+		t.Run("全ての返り値が解決される", func(t *testing.T) {
 			sut := dijct.NewContainer()
 			if err := sut.Register(NewService1With2); err != nil {
 				t.Fatal()
@@ -189,7 +212,7 @@ func Test_container_Invoke(t *testing.T) {
 			if err := sut.Invoke(func(service1 Service1) {}); err != nil {
 				t.Fatal()
 			}
-			if err := sut.Invoke(func(service2 Service2) {}); !dijct.IsErrInvalidResolveComponent(err) {
+			if err := sut.Invoke(func(service2 Service2) {}); err != nil {
 				t.Fatal()
 			}
 		})
@@ -198,7 +221,8 @@ func Test_container_Invoke(t *testing.T) {
 			if err := sut.Register(NewService1With2WithError); err != nil {
 				t.Fatal()
 			}
-			if err := sut.Invoke(func(service1 Service1) {}); err.Error() != "NewService1With2WithError Error" {
+			var e *dijct.ConstructorError
+			if err := sut.Invoke(func(service1 Service1) {}); !errors.As(err, &e) || e.Err.Error() != "NewService1With2WithError Error" {
 				t.Fatal()
 			}
 		})
@@ -207,10 +231,96 @@ func Test_container_Invoke(t *testing.T) {
 			if err := sut.Register(NewService1With2WithError, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
 				t.Fatal()
 			}
-			if err := sut.Invoke(func(service1 Service1) {}); err.Error() != "NewService1With2WithError Error" {
+			var e *dijct.ConstructorError
+			if err := sut.Invoke(func(service1 Service1) {}); !errors.As(err, &e) || e.Err.Error() != "NewService1With2WithError Error" {
+				t.Fatal()
+			}
+		})
+	})
+}
+func Test_container_MultipleOutputs(t *testing.T) {
+	t.Run("1回の Invoke で返り値は同一の呼び出しから解決されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		count := 0
+		if err := sut.Register(func() (Service1, Service2) {
+			count++
+			return NewService1With2()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, service2 Service2) {}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service2 Service2) {}); err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 {
+			t.Fatal(count)
+		}
+	})
+	t.Run("ContainerManaged の場合はコンストラクタが1度だけ呼ばれること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		count := 0
+		if err := sut.Register(func() (Service1, Service2, error) {
+			count++
+			s1, s2 := NewService1With2()
+			return s1, s2, nil
+		}, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		var s1 Service1
+		var s2 Service2
+		if err := sut.Invoke(func(service2 Service2) {
+			s2 = service2
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, service2 Service2) {
+			s1 = service1
+			if s2.GetID() != service2.GetID() {
 				t.Fatal()
 			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 || s1.GetName() != "service1" {
+			t.Fatal(count, s1.GetName())
+		}
+	})
+	t.Run("インターフェイスは代入可能な最初の返り値に割り当てられること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() (*service3, Service2) {
+			return &service3{id: "id", name: "service3"}, NewService2()
+		}, dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service2 Service2, service3 Service3) {
+			if service2.GetName() != "service2" || service3.GetName() != "service3" {
+				t.Fatal(service2.GetName(), service3.GetName())
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("インターフェイスが割り当てられないポインタを返す場合はエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		err := sut.Register(func() (Service1, *service3) {
+			return NewService1(), &service3{}
 		})
+		if !errors.Is(err, dijct.ErrNeedInterfaceOnPointerRegistering) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("返り値が error のみの関数は登録できないこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() error { return nil }); !errors.Is(err, dijct.ErrRequireResponse) {
+			t.Fatal(err)
+		}
 	})
 }
 func Test_container_CreateChildContainer(t *testing.T) {
@@ -430,12 +540,122 @@ func Test_container_CreateChildContainer(t *testing.T) {
 		}
 	})
 }
+func Test_container_CreateChildContainer_Singleton(t *testing.T) {
+	t.Run("子コンテナで最初に生成した親コンテナのシングルトンを親コンテナと共有すること", func(t *testing.T) {
+		t.Parallel()
+		container := dijct.NewContainer()
+		if err := container.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		sut := container.CreateChildContainer()
+		s1 := dijct.MustResolve[Service1](sut)
+		if s2 := dijct.MustResolve[Service1](container); s1.GetID() != s2.GetID() {
+			t.Fatal(s1.GetID(), s2.GetID())
+		}
+	})
+	t.Run("子コンテナの生成後に親コンテナで生成したシングルトンを子コンテナと共有すること", func(t *testing.T) {
+		t.Parallel()
+		container := dijct.NewContainer()
+		if err := container.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		sut := container.CreateChildContainer().CreateChildContainer()
+		s1 := dijct.MustResolve[Service1](container)
+		if s2 := dijct.MustResolve[Service1](sut); s1.GetID() != s2.GetID() {
+			t.Fatal(s1.GetID(), s2.GetID())
+		}
+	})
+	t.Run("子コンテナの生成後に親コンテナで登録したコンポーネントを子コンテナで解決できること", func(t *testing.T) {
+		t.Parallel()
+		container := dijct.NewContainer()
+		sut := container.CreateChildContainer()
+		if err := container.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := dijct.Resolve[Service1](sut); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("親コンテナのシングルトンの依存先は親コンテナから解決されること", func(t *testing.T) {
+		t.Parallel()
+		container := dijct.NewContainer()
+		if err := container.Register(NewNestedService, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := container.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		if err := container.Register(NewService2); err != nil {
+			t.Fatal(err)
+		}
+		if err := container.Register(NewService3(), dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}}); err != nil {
+			t.Fatal(err)
+		}
+		sut := container.CreateChildContainer()
+		if err := sut.Register(NewService2(), dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service1)(nil)).Elem()}}); err != nil {
+			t.Fatal(err)
+		}
+		ns := dijct.MustResolve[NestedService](sut)
+		if ns.GetService1().GetName() != "service1" {
+			t.Fatal(ns.GetService1().GetName())
+		}
+		if s1 := dijct.MustResolve[Service1](sut); s1.GetName() != "service2" {
+			t.Fatal(s1.GetName())
+		}
+		if s1 := dijct.MustResolve[Service1](container); s1.GetName() != "service1" {
+			t.Fatal(s1.GetName())
+		}
+	})
+	t.Run("子コンテナの Multiple の登録は親コンテナの登録に追加されること", func(t *testing.T) {
+		t.Parallel()
+		container := dijct.NewContainer()
+		if err := container.Register(NewService1, dijct.RegisterOptions{Multiple: true}); err != nil {
+			t.Fatal(err)
+		}
+		sut := container.CreateChildContainer()
+		if err := sut.Register(NewService2, dijct.RegisterOptions{Multiple: true, Interfaces: []reflect.Type{reflect.TypeOf((*Service1)(nil)).Elem()}}); err != nil {
+			t.Fatal(err)
+		}
+		if services := dijct.MustResolve[[]Service1](sut); len(services) != 2 || services[0].GetName() != "service1" || services[1].GetName() != "service2" {
+			t.Fatal(services)
+		}
+		if services := dijct.MustResolve[[]Service1](container); len(services) != 1 {
+			t.Fatal(services)
+		}
+	})
+	t.Run("子コンテナの Close では親コンテナのシングルトンを破棄しないこと", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		container := dijct.NewContainer()
+		if err := container.Register(func() *closableService {
+			return NewClosableService("parent", log, nil)
+		}, dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service1)(nil)).Elem()}, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		sut := container.CreateChildContainer()
+		if err := sut.Register(func() *closableService {
+			return NewClosableService("child", log, nil)
+		}, dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service2)(nil)).Elem()}, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(service1 Service1, service2 Service2) {}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if names := log.Names(); !reflect.DeepEqual(names, []string{"child"}) {
+			t.Fatal(names)
+		}
+	})
+}
 func Test_container_Register(t *testing.T) {
 	t.Run("返り値がない関数を登録しようとした場合", func(t *testing.T) {
 		sut := dijct.NewContainer()
 		err := sut.Register(func() {
 		})
-		if err == nil || err != dijct.ErrRequireResponse {
+		var e *dijct.RegistrationError
+		if !errors.As(err, &e) || !errors.Is(err, dijct.ErrRequireResponse) {
 			t.Fatal(err)
 		}
 	})
@@ -446,17 +666,76 @@ func Test_container_Register(t *testing.T) {
 		err := sut.Register(func() string {
 			return ""
 		}, opt1, opt2)
-		if err == nil || err != dijct.ErrNoMultipleOption {
+		var e *dijct.RegistrationError
+		if !errors.As(err, &e) || !errors.Is(err, dijct.ErrNoMultipleOption) {
 			t.Fatal(err)
 		}
 	})
 	t.Run("ポインタを登録する場合は、インターフェイスを指定する必要があること", func(t *testing.T) {
 		sut := dijct.NewContainer()
 		err := sut.Register(NewService3())
-		if err == nil || err != dijct.ErrNeedInterfaceOnPointerRegistering {
+		var e *dijct.RegistrationError
+		if !errors.As(err, &e) || !errors.Is(err, dijct.ErrNeedInterfaceOnPointerRegistering) {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_Errors(t *testing.T) {
+	t.Run("コンストラクタのエラーに解決経路が含まれること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewNestedService); err != nil {
+			t.Fatal(err)
+		}
+		e := errors.New("service2 error")
+		if err := sut.Register(func() (Service2, error) { return nil, e }); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Invoke(func(nestedService NestedService) {})
+		if !errors.Is(err, e) {
+			t.Fatal(err)
+		}
+		var ce *dijct.ConstructorError
+		if !errors.As(err, &ce) {
+			t.Fatal(err)
+		}
+		if ce.Type != reflect.TypeOf((*Service2)(nil)).Elem() || len(ce.Path) != 2 || ce.Path[0] != reflect.TypeOf((*NestedService)(nil)).Elem() {
+			t.Fatal(ce.Type, ce.Path)
+		}
+		if err.Error() != "コンストラクタがエラーを返しました。(dijcttest.NestedService -> dijcttest.Service2): service2 error" {
+			t.Fatal(err)
+		}
+	})
+	t.Run("解決できないタイプと解決経路がエラーに含まれること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewNestedService); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Invoke(func(nestedService NestedService) {})
+		if !dijct.IsErrInvalidResolveComponent(err) || !errors.Is(err, dijct.ErrNotRegisteredComponent) {
+			t.Fatal(err)
+		}
+		var re *dijct.ResolveError
+		if !errors.As(err, &re) || re.Type != reflect.TypeOf((*Service2)(nil)).Elem() {
+			t.Fatal(err)
+		}
+		if err.Error() != "指定されたタイプを解決できません。(dijcttest.NestedService -> dijcttest.Service2): コンポーネントが登録されていません" {
 			t.Fatal(err)
 		}
 	})
+	t.Run("nil はいずれのエラーにも該当しないこと", func(t *testing.T) {
+		t.Parallel()
+		if dijct.IsErrInvalidResolveComponent(nil) || dijct.IsErrCircularDependency(nil) {
+			t.Fatal()
+		}
+	})
 }
 func Test_container_Verify(t *testing.T) {
 	t.Run("Verify できること1", func(t *testing.T) {
@@ -509,4 +788,244 @@ func Test_container_Verify(t *testing.T) {
 			t.Fatal(err)
 		}
 	})
+	t.Run("Verify はコンストラクタを呼び出さないこと", func(t *testing.T) {
+		sut := dijct.NewContainer()
+		count := 0
+		if err := sut.Register(func() Service1 {
+			count++
+			return NewService1()
+		}, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1WithUseCase); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Fatal(count)
+		}
+	})
+	t.Run("Verify は全ての問題をまとめて返すこと", func(t *testing.T) {
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewUseCase); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewNestedService); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1WithUseCase); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		err := sut.Verify()
+		var e *dijct.VerificationReport
+		if !errors.As(err, &e) {
+			t.Fatal(err)
+		}
+		if !dijct.IsErrCircularDependency(err) || !dijct.IsErrInvalidResolveComponent(err) || dijct.IsErrCaptiveDependency(err) {
+			t.Fatal(err)
+		}
+		useCaseType := reflect.TypeOf((*UseCase)(nil)).Elem()
+		nestedServiceType := reflect.TypeOf((*NestedService)(nil)).Elem()
+		service1Type := reflect.TypeOf((*Service1)(nil)).Elem()
+		service3Type := reflect.TypeOf((*Service3)(nil)).Elem()
+		entries := e.Entries()
+		if len(entries) != 3 {
+			t.Fatal(entries)
+		}
+		if entries[0].Type != useCaseType || entries[0].Consumer != service1Type || !dijct.IsErrCircularDependency(entries[0].Err) {
+			t.Fatal(entries[0])
+		}
+		if entries[1].Type != service3Type || entries[1].Consumer != nestedServiceType || !dijct.IsErrInvalidResolveComponent(entries[1].Err) {
+			t.Fatal(entries[1])
+		}
+		if entries[2].Type != service3Type || entries[2].Consumer != useCaseType || !dijct.IsErrInvalidResolveComponent(entries[2].Err) {
+			t.Fatal(entries[2])
+		}
+		expected := `登録内容の検証に失敗しました。(3件)
+  - dijcttest.Service1 が必要とする dijcttest.UseCase: 依存関係が循環しています。(dijcttest.UseCase -> dijcttest.NestedService -> dijcttest.Service1 -> dijcttest.UseCase)
+  - dijcttest.NestedService が必要とする dijcttest.Service3: 指定されたタイプを解決できません。(dijcttest.UseCase -> dijcttest.NestedService -> dijcttest.Service3): コンポーネントが登録されていません
+  - dijcttest.UseCase が必要とする dijcttest.Service3: 指定されたタイプを解決できません。(dijcttest.UseCase -> dijcttest.Service3): コンポーネントが登録されていません`
+		if err.Error() != expected {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Scope は スコープから Verify した場合のみ解決できること", func(t *testing.T) {
+		sut := dijct.NewContainer()
+		if err := sut.Register(func(scope dijct.Scope, ctx context.Context, locator dijct.ServiceLocator) Service1 {
+			return NewService1()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Verify(); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+		if err := sut.BeginScope().Verify(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_WarmUp(t *testing.T) {
+	t.Run("WarmUp は全てのコンポーネントを生成して ContainerManaged のインスタンスをキャッシュすること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		count := 0
+		if err := sut.Register(func() Service1 {
+			count++
+			return NewService1()
+		}, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.WarmUp(); err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Fatal(count)
+		}
+		if err := sut.Invoke(func(service1 Service1) {}); err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Fatal(count)
+		}
+	})
+	t.Run("WarmUp はコンストラクタのエラーを返すこと", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewService1With2WithError); err != nil {
+			t.Fatal(err)
+		}
+		var e *dijct.ConstructorError
+		if err := sut.WarmUp(); !errors.As(err, &e) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("登録がない場合は WarmUp がエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.WarmUp(); err != dijct.ErrNotFoundComponent {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_CircularDependency(t *testing.T) {
+	setup := func(t *testing.T, options ...dijct.RegisterOptions) dijct.Container {
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewUseCase, options...); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewNestedService, options...); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1WithUseCase, options...); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, options...); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3(), dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}}); err != nil {
+			t.Fatal(err)
+		}
+		return sut
+	}
+	t.Run("Invoke で循環参照がエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t)
+		err := sut.Invoke(func(useCase UseCase) {})
+		var e *dijct.CircularDependencyError
+		if !errors.As(err, &e) {
+			t.Fatal(err)
+		}
+		if len(e.Path) != 4 || e.Path[0] != e.Path[3] {
+			t.Fatal(e.Path)
+		}
+		if err.Error() != "依存関係が循環しています。(dijcttest.UseCase -> dijcttest.NestedService -> dijcttest.Service1 -> dijcttest.UseCase)" {
+			t.Fatal(err)
+		}
+	})
+	t.Run("循環の途中から解決した場合も循環部分のみが示されること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t)
+		err := sut.Invoke(func(service1 Service1) {})
+		if !dijct.IsErrCircularDependency(err) {
+			t.Fatal(err)
+		}
+		if err.Error() != "依存関係が循環しています。(dijcttest.Service1 -> dijcttest.UseCase -> dijcttest.NestedService -> dijcttest.Service1)" {
+			t.Fatal(err)
+		}
+	})
+	t.Run("ContainerManaged の場合も循環参照がエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged})
+		if err := sut.Invoke(func(useCase UseCase) {}); !dijct.IsErrCircularDependency(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Verify で循環参照がエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t)
+		if err := sut.Verify(); !dijct.IsErrCircularDependency(err) {
+			t.Fatal(err)
+		}
+	})
+}
+func Test_container_Transient(t *testing.T) {
+	t.Run("Transient の場合は依存先として解決される度に異なるインスタンスが生成されること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewUseCase); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewNestedService); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.Transient}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3(), dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(useCase UseCase, service1 Service1) {
+			ids := map[string]bool{
+				useCase.GetService1().GetID():                    true,
+				useCase.GetNestedService().GetService1().GetID(): true,
+				service1.GetID():                                 true,
+			}
+			if len(ids) != 3 {
+				t.Fatal(ids)
+			}
+			if useCase.GetService2().GetID() != useCase.GetNestedService().GetService2().GetID() {
+				t.Fatal()
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Transient のインスタンスは Invoke の終了時に破棄されること", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		sut := dijct.NewContainer()
+		if err := sut.Register(func() *closableService {
+			return NewClosableService("transient", log, nil)
+		}, dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service1)(nil)).Elem()}, LifetimeScope: dijct.Transient}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(a Service1, b Service1) {
+			if a.GetID() == b.GetID() {
+				t.Fatal()
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if len(log.Names()) != 2 {
+			t.Fatal(log.Names())
+		}
+	})
 }
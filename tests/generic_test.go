@@ -0,0 +1,203 @@
+package dijcttest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+type valueService string
+
+func (v valueService) GetID() string   { return string(v) }
+func (v valueService) GetName() string { return string(v) }
+
+type otherValueService string
+
+func (v otherValueService) GetID() string   { return string(v) }
+func (v otherValueService) GetName() string { return string(v) }
+
+func Test_generic(t *testing.T) {
+	t.Run("Provide で定数をインターフェイスとして登録できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		service3 := NewService3()
+		if err := dijct.Provide[Service3](sut, service3); err != nil {
+			t.Fatal(err)
+		}
+		v, err := dijct.Resolve[Service3](sut)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.GetID() != service3.GetID() {
+			t.Fatal(v.GetID(), service3.GetID())
+		}
+	})
+	t.Run("Provide で関数を定数として登録できること", func(t *testing.T) {
+		t.Parallel()
+		type counter func() int
+		type closer func() (int, func())
+		sut := dijct.NewContainer()
+		if err := dijct.Provide[counter](sut, counter(func() int { return 1 })); err != nil {
+			t.Fatal(err)
+		}
+		if err := dijct.Provide[closer](sut, func() (int, func()) { return 2, func() {} }); err != nil {
+			t.Fatal(err)
+		}
+		if v := dijct.MustResolve[counter](sut); v() != 1 {
+			t.Fatal(v())
+		}
+		if v, _ := dijct.MustResolve[closer](sut)(); v != 2 {
+			t.Fatal(v)
+		}
+		if r := sut.Registrations(); r[0].LifetimeScope != dijct.ContainerManaged || r[0].File != "" {
+			t.Fatal(r)
+		}
+	})
+	t.Run("Provide で nil をインターフェイスとして登録できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := dijct.Provide[Service3](sut, nil); err != nil {
+			t.Fatal(err)
+		}
+		v, err := dijct.Resolve[Service3](sut)
+		if err != nil || v != nil {
+			t.Fatal(v, err)
+		}
+		if err := sut.Invoke(func(service3 Service3) {
+			if service3 != nil {
+				t.Fatal(service3)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Register で nil を指定した場合はエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		var e *dijct.RegistrationError
+		if err := dijct.Register[Service1](sut, nil); !errors.As(err, &e) || !errors.Is(err, dijct.ErrRequireFunction) {
+			t.Fatal(err)
+		}
+		if err := dijct.RegisterAs[Service1, Service1](sut, nil); !errors.As(err, &e) || !errors.Is(err, dijct.ErrRequireFunction) {
+			t.Fatal(err)
+		}
+		if err := sut.Register(nil); !errors.As(err, &e) || !errors.Is(err, dijct.ErrRequireFunction) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Register でコンストラクタをインターフェイスとして登録できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := dijct.Register[Service1](sut, NewService2, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		s1 := dijct.MustResolve[Service1](sut)
+		s2 := dijct.MustResolve[Service2](sut)
+		if s1.GetName() != "service2" || s2.GetName() != "service2" {
+			t.Fatal(s1.GetName(), s2.GetName())
+		}
+	})
+	t.Run("オプションを指定しない場合は Container.Register と同じライフタイムで登録すること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		count := 0
+		if err := dijct.Register[Service1](sut, func() Service1 {
+			count++
+			return NewService1()
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if err := sut.Invoke(func(service1 Service1) {}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if count != 2 {
+			t.Fatal(count)
+		}
+		registrations := sut.Registrations()
+		if registrations[0].LifetimeScope != dijct.InvokeManaged || registrations[0].LifetimeScope != registrations[1].LifetimeScope {
+			t.Fatal(registrations)
+		}
+	})
+	t.Run("RegisterAs で実装を返すコンストラクタをインターフェイスとして登録できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := dijct.RegisterAs[Service3, *service3](sut, func() *service3 {
+			return &service3{id: "id", name: "service3"}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if v := dijct.MustResolve[Service3](sut); v.GetID() != "id" {
+			t.Fatal(v.GetID())
+		}
+	})
+	t.Run("RegisterAs で複数の返り値のうち T の返り値を I として登録すること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := dijct.RegisterAs[Service1, otherValueService](sut, func() (valueService, otherValueService) {
+			return "a", "b"
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if v := dijct.MustResolve[Service1](sut); v.GetID() != "b" {
+			t.Fatal(v.GetID())
+		}
+		if v := dijct.MustResolve[valueService](sut); v != "a" {
+			t.Fatal(v)
+		}
+
+		if err := dijct.RegisterAs[Service1, *service2](sut, func() (*service1, *service2) {
+			return &service1{id: "a"}, &service2{id: "b"}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if v := dijct.MustResolve[Service1](sut); v.GetID() != "b" {
+			t.Fatal(v.GetID())
+		}
+	})
+	t.Run("RegisterAs でコンストラクタの返り値が一致しない場合はエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		err := dijct.RegisterAs[Service3, *service3](sut, NewService3)
+		var e *dijct.RegistrationError
+		if !errors.As(err, &e) || !errors.Is(err, dijct.ErrNotAssignable) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("インターフェイスを実装していない場合はエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if err := dijct.Register[UseCase](sut, NewService1); !errors.Is(err, dijct.ErrNotAssignable) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Resolve で解決できない場合はエラーになること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if _, err := dijct.Resolve[Service1](sut); !dijct.IsErrInvalidResolveComponent(err) {
+			t.Fatal(err)
+		}
+	})
+	t.Run("MustResolve で解決できない場合は panic すること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		defer func() {
+			if err, ok := recover().(error); !ok || !dijct.IsErrInvalidResolveComponent(err) {
+				t.Fatal(err)
+			}
+		}()
+		dijct.MustResolve[Service1](sut)
+	})
+	t.Run("コンテナ自身を Resolve できること", func(t *testing.T) {
+		t.Parallel()
+		sut := dijct.NewContainer()
+		if v := dijct.MustResolve[dijct.ServiceLocator](sut); v != sut {
+			t.Fatal()
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package dijcttest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_container_Graph(t *testing.T) {
+	setup := func(t *testing.T) dijct.Container {
+		t.Helper()
+		parent := dijct.NewContainer()
+		if err := parent.Register(NewService2, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := parent.Register(NewService3(), dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}}); err != nil {
+			t.Fatal(err)
+		}
+		sut := parent.CreateChildContainer()
+		if err := sut.Register(NewNestedService); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.InvokeManaged, Multiple: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.Transient, Multiple: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(func(services []Service1, container dijct.Container) UseCase {
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return sut
+	}
+	t.Run("DOT 形式で依存関係を出力できること", func(t *testing.T) {
+		t.Parallel()
+		expected := `digraph dijct {
+	"dijcttest.NestedService" [label="dijcttest.NestedService\nInvokeManaged", shape=ellipse, style=solid];
+	"dijcttest.Service1#0" [label="dijcttest.Service1#0\nInvokeManaged", shape=ellipse, style=solid];
+	"dijcttest.Service1#1" [label="dijcttest.Service1#1\nTransient", shape=ellipse, style=solid];
+	"dijcttest.Service2" [label="dijcttest.Service2\nContainerManaged", shape=ellipse, style=dashed];
+	"dijcttest.Service3" [label="dijcttest.Service3\nContainerManaged", shape=box, style=dashed];
+	"dijcttest.UseCase" [label="dijcttest.UseCase\nInvokeManaged", shape=ellipse, style=solid];
+	"dijcttest.NestedService" -> "dijcttest.Service1#1";
+	"dijcttest.NestedService" -> "dijcttest.Service2";
+	"dijcttest.NestedService" -> "dijcttest.Service3";
+	"dijcttest.UseCase" -> "dijcttest.Service1#0";
+	"dijcttest.UseCase" -> "dijcttest.Service1#1";
+}
+`
+		if actual := setup(t).Graph().DOT(); actual != expected {
+			t.Fatal(actual)
+		}
+	})
+	t.Run("JSON 形式で依存関係を出力できること", func(t *testing.T) {
+		t.Parallel()
+		parent := dijct.NewContainer()
+		if err := parent.Register(NewService3(), dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}, Name: "const"}); err != nil {
+			t.Fatal(err)
+		}
+		sut := parent.CreateChildContainer()
+		if err := sut.Register(func(services map[string]Service3) Service1 {
+			return NewService1()
+		}, dijct.RegisterOptions{LifetimeScope: dijct.ScopeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		expected := `{
+  "nodes": [
+    {
+      "id": "dijcttest.Service1",
+      "type": "dijcttest.Service1",
+      "lifetimeScope": "ScopeManaged",
+      "constructor": true,
+      "inherited": false
+    },
+    {
+      "id": "dijcttest.Service3[const]",
+      "type": "dijcttest.Service3",
+      "name": "const",
+      "lifetimeScope": "ContainerManaged",
+      "constructor": false,
+      "inherited": true
+    }
+  ],
+  "edges": [
+    {
+      "from": "dijcttest.Service1",
+      "to": "dijcttest.Service3[const]"
+    }
+  ]
+}`
+		actual, err := sut.Graph().JSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != expected {
+			t.Fatal(string(actual))
+		}
+	})
+	t.Run("登録がない場合は空のグラフになること", func(t *testing.T) {
+		t.Parallel()
+		actual, err := dijct.NewContainer().Graph().JSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != "{\n  \"nodes\": [],\n  \"edges\": []\n}" {
+			t.Fatal(string(actual))
+		}
+	})
+}
@@ -2,6 +2,7 @@ package dijcttest
 
 import (
 	"errors"
+	"sync"
 
 	"github.com/google/uuid"
 )
@@ -197,3 +198,89 @@ func NewService1With2() (Service1, Service2) {
 func NewService1With2WithError() (Service1, Service2, error) {
 	return &service1{id: uuid.New().String(), name: "service1"}, &service2{id: uuid.New().String(), name: "service2"}, errors.New("NewService1With2WithError Error")
 }
+
+// NewService1WithUseCase is
+func NewService1WithUseCase(useCase UseCase) Service1 {
+	return &service1{id: useCase.GetID(), name: "service1"}
+}
+
+type service1Wrapper struct {
+	Service1
+}
+
+// GetName is
+func (s *service1Wrapper) GetName() string {
+	return "wrapped " + s.Service1.GetName()
+}
+
+type (
+	// CloseLog is
+	CloseLog struct {
+		mu    sync.Mutex
+		names []string
+	}
+	closableService struct {
+		id   string
+		name string
+		log  *CloseLog
+		err  error
+	}
+)
+
+// Add is
+func (log *CloseLog) Add(name string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.names = append(log.names, name)
+}
+
+// Names is
+func (log *CloseLog) Names() []string {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	return append([]string{}, log.names...)
+}
+
+// NewClosableService is
+func NewClosableService(name string, log *CloseLog, err error) *closableService {
+	return &closableService{id: uuid.New().String(), name: name, log: log, err: err}
+}
+
+// GetID is
+func (closableService *closableService) GetID() string {
+	return closableService.id
+}
+
+// GetName is
+func (closableService *closableService) GetName() string {
+	return closableService.name
+}
+
+// Close is
+func (closableService *closableService) Close() error {
+	closableService.log.Add(closableService.name)
+	return closableService.err
+}
+
+type (
+	// TaggedService is
+	TaggedService struct {
+		Service1 Service1 `dijct:""`
+		Service2 Service2 `dijct:"name=second"`
+		Service3 Service3 `dijct:"optional"`
+		Untagged Service1
+	}
+	unexportedTaggedService struct {
+		service1 Service1 `dijct:""`
+	}
+)
+
+// NewTaggedService is
+func NewTaggedService() *TaggedService {
+	return &TaggedService{}
+}
+
+// GetService1 is
+func (s *unexportedTaggedService) GetService1() Service1 {
+	return s.service1
+}
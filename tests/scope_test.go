@@ -0,0 +1,158 @@
+package dijcttest
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/wakuwaku3/dijct"
+)
+
+func Test_scope(t *testing.T) {
+	setup := func(t *testing.T) dijct.Container {
+		sut := dijct.NewContainer()
+		if err := sut.Register(NewNestedService, dijct.RegisterOptions{LifetimeScope: dijct.ScopeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService1, dijct.RegisterOptions{LifetimeScope: dijct.InvokeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService2, dijct.RegisterOptions{LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Register(NewService3(), dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service3)(nil)).Elem()}}); err != nil {
+			t.Fatal(err)
+		}
+		return sut
+	}
+	t.Run("スコープ内では ScopeManaged のインスタンスが共有されること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t).BeginScope()
+		var ns NestedService
+		var s1 Service1
+		if err := sut.Invoke(func(nestedService NestedService, service1 Service1) {
+			ns = nestedService
+			s1 = service1
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(nestedService NestedService, service1 Service1) {
+			if ns.GetID() != nestedService.GetID() {
+				t.Fatal(ns.GetID(), nestedService.GetID())
+			}
+			if s1.GetID() == service1.GetID() {
+				t.Fatal()
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("スコープ間では ScopeManaged のインスタンスが分離され ContainerManaged は共有されること", func(t *testing.T) {
+		t.Parallel()
+		container := setup(t)
+		ns1 := dijct.MustResolve[NestedService](container.BeginScope())
+		ns2 := dijct.MustResolve[NestedService](container.BeginScope())
+		if ns1.GetID() == ns2.GetID() {
+			t.Fatal()
+		}
+		if ns1.GetService2().GetID() != ns2.GetService2().GetID() || ns1.GetService2().GetID() != dijct.MustResolve[Service2](container).GetID() {
+			t.Fatal()
+		}
+	})
+	t.Run("スコープ外では ScopeManaged は InvokeManaged と同様に扱われること", func(t *testing.T) {
+		t.Parallel()
+		sut := setup(t)
+		var ns NestedService
+		if err := sut.Invoke(func(nestedService NestedService, useNestedService NestedService) {
+			if nestedService.GetID() != useNestedService.GetID() {
+				t.Fatal()
+			}
+			ns = nestedService
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.Invoke(func(nestedService NestedService) {
+			if ns.GetID() == nestedService.GetID() {
+				t.Fatal()
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("スコープ内ではスコープ自身を解決できること", func(t *testing.T) {
+		t.Parallel()
+		container := setup(t)
+		sut := container.BeginScope()
+		if err := sut.Invoke(func(scope dijct.Scope, serviceLocator dijct.ServiceLocator, c dijct.Container) {
+			if scope != sut || serviceLocator != sut || c != container {
+				t.Fatal()
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("Close でスコープが生成したインスタンスのみ破棄されること", func(t *testing.T) {
+		t.Parallel()
+		log := &CloseLog{}
+		container := dijct.NewContainer()
+		if err := container.Register(func(service2 Service2) *closableService {
+			return NewClosableService("scoped", log, nil)
+		}, dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service1)(nil)).Elem()}, LifetimeScope: dijct.ScopeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		if err := container.Register(func() *closableService {
+			return NewClosableService("singleton", log, nil)
+		}, dijct.RegisterOptions{Interfaces: []reflect.Type{reflect.TypeOf((*Service2)(nil)).Elem()}, LifetimeScope: dijct.ContainerManaged}); err != nil {
+			t.Fatal(err)
+		}
+		sut := container.BeginScope()
+		for i := 0; i < 2; i++ {
+			if err := sut.Invoke(func(service1 Service1) {}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(log.Names()) != 0 {
+			t.Fatal(log.Names())
+		}
+		if err := sut.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if names := log.Names(); !reflect.DeepEqual(names, []string{"scoped"}) {
+			t.Fatal(names)
+		}
+		if err := container.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if names := log.Names(); !reflect.DeepEqual(names, []string{"scoped", "singleton"}) {
+			t.Fatal(names)
+		}
+	})
+	t.Run("スコープ内で並行に解決しても ScopeManaged のコンストラクタは1度だけ呼ばれること", func(t *testing.T) {
+		t.Parallel()
+		container := dijct.NewContainer()
+		var count int32
+		if err := container.Register(func() Service1 {
+			atomic.AddInt32(&count, 1)
+			return NewService1()
+		}, dijct.RegisterOptions{LifetimeScope: dijct.ScopeManaged}); err != nil {
+			t.Fatal(err)
+		}
+		sut := container.BeginScope()
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := sut.Invoke(func(service1 Service1) {}); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+		if count != 1 {
+			t.Fatal(count)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package dijct
+
+import "context"
+
+type (
+	// verifier はコンストラクタを呼び出さずに factoryInfo の依存先を辿って登録内容を検証します
+	verifier struct {
+		container *container
+		rc        *resolveContext
+		done      map[decoratedKey]bool
+		entries   []VerificationEntry
+	}
+)
+
+// Verify はコンストラクタを呼び出さずに全てのコンポーネントの依存関係を検証します。
+// 登録されていない依存先、依存関係の循環、ライフタイムの短いコンポーネントへの依存の全てを VerificationReport にまとめて返します
+func (c *container) Verify() error {
+	return c.verify(nil)
+}
+func (c *container) verify(s *scope) error {
+	if c.err != nil {
+		return c.err
+	}
+	elems := c.getAllComponents()
+	if len(elems) == 0 {
+		return ErrNotFoundComponent
+	}
+	v := &verifier{
+		container: c,
+		rc:        newResolveContext(context.Background(), s),
+		done:      make(map[decoratedKey]bool),
+	}
+	for _, elem := range elems {
+		v.visit(elem.key, elem.component.factoryInfo)
+	}
+	if len(v.entries) == 0 {
+		return nil
+	}
+	return &VerificationReport{entries: v.entries}
+}
+func (v *verifier) visit(key componentKey, f *factoryInfo) {
+	if i := v.rc.resolving(f); i >= 0 {
+		v.report(key, newCircularDependencyError(v.rc.path[i:], key))
+		return
+	}
+	done := decoratedKey{factoryInfo: f, t: key.t}
+	if v.done[done] {
+		return
+	}
+	v.rc.push(key, f)
+	defer v.rc.pop()
+	for _, d := range append(f.dependencies(), v.container.getDecoratorDependencies(key.t)...) {
+		if v.container.isBuiltin(d.key, v.rc.scope) {
+			continue
+		}
+		deps := v.container.getDependencies(d.key)
+		if len(deps) == 0 {
+			if _, ok := unwrapOptional(d.key); !d.optional && !ok {
+				v.report(d.key, newResolveError(d.key, v.rc.path, ErrNotRegisteredComponent))
+			}
+			continue
+		}
+		// Lazy[T] と func() (T, error) は生成時に解決しないため、循環の検出では辿りません
+		_, isDeferred := unwrapDeferred(d.key)
+		for _, dep := range deps {
+			if isCaptive(f, dep.component.factoryInfo) {
+				v.report(dep.key, newCaptiveDependencyError(key, f, dep.key, dep.component.factoryInfo))
+			}
+			if !isDeferred {
+				v.visit(dep.key, dep.component.factoryInfo)
+			}
+		}
+	}
+	v.done[done] = true
+}
+
+// report は解決中のコンポーネントが key を必要とした問題として err を記録します
+func (v *verifier) report(key componentKey, err error) {
+	consumer := v.rc.path[len(v.rc.path)-1]
+	v.entries = append(v.entries, VerificationEntry{Type: key.t, Name: key.name, Consumer: consumer.t, ConsumerName: consumer.name, Err: err})
+}
+
+// isBuiltin は key が登録によらずに解決されるタイプかを返します
+func (c *container) isBuiltin(key componentKey, s *scope) bool {
+	if key.name != "" {
+		return false
+	}
+	if key.t == contextType {
+		return true
+	}
+	if c.options.DisableAutoInjection {
+		return false
+	}
+	switch key.t {
+	case c.containerInterfaceType, c.ioCContainerInterfaceType, c.serviceLocatorInterfaceType:
+		return true
+	case c.scopeInterfaceType:
+		return s != nil
+	}
+	return false
+}
+
+// WarmUp は全てのコンポーネントを実際に解決します。
+// ContainerManaged のインスタンスはキャッシュされ、InvokeManaged のインスタンスは解決後に破棄します
+func (c *container) WarmUp() error {
+	return c.warmUp(newResolveContext(context.Background(), nil))
+}
+func (c *container) warmUp(rc *resolveContext) (err error) {
+	if c.err != nil {
+		return c.err
+	}
+	elems := c.getAllComponents()
+	if len(elems) == 0 {
+		return ErrNotFoundComponent
+	}
+	defer func() {
+		if _, e := disposeAll(context.Background(), rc.invocation.close()); e != nil && err == nil {
+			err = e
+		}
+	}()
+	for _, elem := range elems {
+		if _, err := c.resolveComponent(elem.key, elem.component, rc); err != nil {
+			return err
+		}
+	}
+	return nil
+}